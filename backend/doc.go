@@ -0,0 +1,4 @@
+// Package backend has the logic to download the current
+// TFState from the remote backends, like the HTTP one or
+// the API of Terraform Cloud and Terraform Enterprise
+package backend
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/cycloidio/inframap/errcode"
+)
+
+// client is the http.Client used for all the requests
+var client = &http.Client{
+	Timeout: time.Minute,
+}
+
+// HTTP downloads the current TFState from the address of an
+// HTTP backend, the username and password are used for the
+// basic auth if any of them is set, as the 'http' backend
+// of Terraform does
+func HTTP(address, username, password string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	if username != "" || password != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	return do(req)
+}
+
+// do sends the req and returns the body of the response,
+// if there is no content it's considered that the backend
+// has no TFState yet
+func do(req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), errcode.ErrBackendNotFoundState)
+	default:
+		return nil, fmt.Errorf("%s %s returned %d: %w", req.Method, req.URL.Redacted(), resp.StatusCode, errcode.ErrBackendInvalidStatus)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the response of %s: %w", req.URL.Redacted(), err)
+	}
+
+	if len(b) == 0 {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), errcode.ErrBackendNotFoundState)
+	}
+
+	return b, nil
+}
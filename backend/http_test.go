@@ -0,0 +1,58 @@
+package backend_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/backend"
+	"github.com/cycloidio/inframap/errcode"
+)
+
+func TestHTTP(t *testing.T) {
+	state, err := ioutil.ReadFile("../generate/testdata/aws_state_sg.json")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/state/network":
+			if u, p, ok := r.BasicAuth(); ok && (u != "user" || p != "pass") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write(state)
+		case "/state/empty":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		b, err := backend.HTTP(srv.URL+"/state/network", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, state, b)
+	})
+	t.Run("SuccessBasicAuth", func(t *testing.T) {
+		b, err := backend.HTTP(srv.URL+"/state/network", "user", "pass")
+		require.NoError(t, err)
+		assert.Equal(t, state, b)
+	})
+	t.Run("ErrBackendInvalidStatus", func(t *testing.T) {
+		_, err := backend.HTTP(srv.URL+"/state/network", "user", "invalid")
+		assert.True(t, errors.Is(err, errcode.ErrBackendInvalidStatus))
+	})
+	t.Run("ErrBackendNotFoundState", func(t *testing.T) {
+		_, err := backend.HTTP(srv.URL+"/state/empty", "", "")
+		assert.True(t, errors.Is(err, errcode.ErrBackendNotFoundState))
+
+		_, err = backend.HTTP(srv.URL+"/state/app", "", "")
+		assert.True(t, errors.Is(err, errcode.ErrBackendNotFoundState))
+	})
+}
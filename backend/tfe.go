@@ -0,0 +1,127 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+)
+
+// DefaultTFEHostname is the hostname of Terraform Cloud
+const DefaultTFEHostname = "app.terraform.io"
+
+// tfeContentType is the content type of the TFE API
+const tfeContentType = "application/vnd.api+json"
+
+// tfeDocument is the representation of the JSON:API documents
+// returned by the TFE API, only with the attributes used
+type tfeDocument struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			HostedStateDownloadURL string `json:"hosted-state-download-url"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// TFE downloads the current TFState of the workspace of the
+// organization from the state versions API of Terraform Cloud
+// or Terraform Enterprise on the hostname, which is 'https'
+// if it has no scheme, using the token to authenticate
+func TFE(hostname, organization, workspace, token string) ([]byte, error) {
+	if hostname == "" {
+		hostname = DefaultTFEHostname
+	}
+	if !strings.Contains(hostname, "://") {
+		hostname = "https://" + hostname
+	}
+	hostname = strings.TrimSuffix(hostname, "/")
+
+	hu, err := url.Parse(hostname)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hostname %q: %w", hostname, err)
+	}
+	host := hu.Host
+
+	ws, err := tfeGet(fmt.Sprintf("%s/api/v2/organizations/%s/workspaces/%s", hostname, url.PathEscape(organization), url.PathEscape(workspace)), host, token)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the workspace %s/%s: %w", organization, workspace, err)
+	}
+
+	var wsd tfeDocument
+	err = json.Unmarshal(ws, &wsd)
+	if err != nil || wsd.Data.ID == "" {
+		return nil, fmt.Errorf("workspace %s/%s: %w", organization, workspace, errcode.ErrBackendInvalidResponse)
+	}
+
+	sv, err := tfeGet(fmt.Sprintf("%s/api/v2/workspaces/%s/current-state-version", hostname, url.PathEscape(wsd.Data.ID)), host, token)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the current state version of %s/%s: %w", organization, workspace, err)
+	}
+
+	var svd tfeDocument
+	err = json.Unmarshal(sv, &svd)
+	if err != nil || svd.Data.Attributes.HostedStateDownloadURL == "" {
+		return nil, fmt.Errorf("state version of %s/%s: %w", organization, workspace, errcode.ErrBackendInvalidResponse)
+	}
+
+	b, err := tfeGet(svd.Data.Attributes.HostedStateDownloadURL, host, token)
+	if err != nil {
+		return nil, fmt.Errorf("error while downloading the state of %s/%s: %w", organization, workspace, err)
+	}
+
+	return b, nil
+}
+
+// ParseTFEWorkspace splits the w, which is 'ORGANIZATION/WORKSPACE',
+// into the organization and the workspace
+func ParseTFEWorkspace(w string) (string, string, error) {
+	ow := strings.Split(w, "/")
+	if len(ow) != 2 || ow[0] == "" || ow[1] == "" {
+		return "", "", fmt.Errorf("workspace %q has to be ORGANIZATION/WORKSPACE: %w", w, errcode.ErrBackendInvalidTFEWorkspace)
+	}
+
+	return ow[0], ow[1], nil
+}
+
+// ParseTFEHostname returns the bare host of the h, which can
+// have the 'https' scheme, like 'https://TFE.example.com/' is
+// 'tfe.example.com' as used on the TF_TOKEN_<hostname>. The
+// ones with other schemes, ports or paths are not valid
+func ParseTFEHostname(h string) (string, error) {
+	u := h
+	if !strings.Contains(u, "://") {
+		u = "https://" + u
+	}
+
+	pu, err := url.Parse(u)
+	if err != nil || pu.Scheme != "https" || pu.Hostname() == "" || pu.Port() != "" || strings.Trim(pu.Path, "/") != "" || pu.User != nil || pu.RawQuery != "" {
+		return "", fmt.Errorf("hostname %q has to be only the host, like %q: %w", h, DefaultTFEHostname, errcode.ErrBackendInvalidTFEHostname)
+	}
+
+	return strings.ToLower(pu.Hostname()), nil
+}
+
+// tfeGet does a GET to the u with the token, which is only
+// sent if the u is on the host of the TFE, as the URLs to
+// download the states can be on another one, like a storage
+func tfeGet(u, host, token string) ([]byte, error) {
+	if token == "" {
+		return nil, errcode.ErrBackendRequiredToken
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", u, err)
+	}
+
+	if strings.EqualFold(req.URL.Host, host) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Content-Type", tfeContentType)
+
+	return do(req)
+}
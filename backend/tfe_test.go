@@ -0,0 +1,118 @@
+package backend_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/backend"
+	"github.com/cycloidio/inframap/errcode"
+)
+
+func TestTFE(t *testing.T) {
+	state, err := ioutil.ReadFile("../generate/testdata/aws_state_sg.json")
+	require.NoError(t, err)
+
+	// storage is another host, like the ones of the hosted
+	// states, so it must not receive the token
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/archivist/sv-storage":
+			w.Write(state)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer storage.Close()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/organizations/cycloid/workspaces/network":
+			fmt.Fprint(w, `{"data":{"id":"ws-network","type":"workspaces"}}`)
+		case "/api/v2/organizations/cycloid/workspaces/storage":
+			fmt.Fprint(w, `{"data":{"id":"ws-storage","type":"workspaces"}}`)
+		case "/api/v2/workspaces/ws-storage/current-state-version":
+			fmt.Fprintf(w, `{"data":{"id":"sv-storage","type":"state-versions","attributes":{"hosted-state-download-url":"%s/archivist/sv-storage"}}}`, storage.URL)
+		case "/api/v2/organizations/cycloid/workspaces/empty":
+			fmt.Fprint(w, `{"data":{"id":"ws-empty","type":"workspaces"}}`)
+		case "/api/v2/workspaces/ws-network/current-state-version":
+			fmt.Fprintf(w, `{"data":{"id":"sv-network","type":"state-versions","attributes":{"hosted-state-download-url":"%s/archivist/sv-network"}}}`, srv.URL)
+		case "/archivist/sv-network":
+			w.Write(state)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		b, err := backend.TFE(srv.URL, "cycloid", "network", "token")
+		require.NoError(t, err)
+		assert.Equal(t, state, b)
+	})
+	t.Run("SuccessOtherHost", func(t *testing.T) {
+		b, err := backend.TFE(srv.URL, "cycloid", "storage", "token")
+		require.NoError(t, err)
+		assert.Equal(t, state, b)
+	})
+	t.Run("ErrBackendNotFoundState", func(t *testing.T) {
+		_, err := backend.TFE(srv.URL, "cycloid", "empty", "token")
+		assert.True(t, errors.Is(err, errcode.ErrBackendNotFoundState))
+	})
+	t.Run("ErrBackendInvalidStatus", func(t *testing.T) {
+		_, err := backend.TFE(srv.URL, "cycloid", "network", "invalid")
+		assert.True(t, errors.Is(err, errcode.ErrBackendInvalidStatus))
+	})
+	t.Run("ErrBackendRequiredToken", func(t *testing.T) {
+		_, err := backend.TFE(srv.URL, "cycloid", "network", "")
+		assert.True(t, errors.Is(err, errcode.ErrBackendRequiredToken))
+	})
+}
+
+func TestParseTFEWorkspace(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		o, w, err := backend.ParseTFEWorkspace("cycloid/network")
+		require.NoError(t, err)
+		assert.Equal(t, "cycloid", o)
+		assert.Equal(t, "network", w)
+	})
+	t.Run("ErrBackendInvalidTFEWorkspace", func(t *testing.T) {
+		for _, w := range []string{"network", "cycloid/", "/network", "cycloid/network/app"} {
+			_, _, err := backend.ParseTFEWorkspace(w)
+			assert.True(t, errors.Is(err, errcode.ErrBackendInvalidTFEWorkspace), w)
+		}
+	})
+}
+
+func TestParseTFEHostname(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		for _, h := range []string{"tfe.example.com", "https://tfe.example.com", "https://tfe.example.com/", "TFE.example.com"} {
+			ph, err := backend.ParseTFEHostname(h)
+			require.NoError(t, err, h)
+			assert.Equal(t, "tfe.example.com", ph, h)
+		}
+	})
+	t.Run("ErrBackendInvalidTFEHostname", func(t *testing.T) {
+		for _, h := range []string{"", "http://tfe.example.com", "tfe.example.com:443", "https://tfe.example.com/api", "https://user@tfe.example.com"} {
+			_, err := backend.ParseTFEHostname(h)
+			assert.True(t, errors.Is(err, errcode.ErrBackendInvalidTFEHostname), h)
+		}
+	})
+}
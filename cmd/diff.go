@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/cycloidio/inframap/generate"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/factory"
+)
+
+var (
+	diffCmd = &cobra.Command{
+		Use:     "diff OLD NEW",
+		Short:   "Generates the Graph of the changes between two inputs",
+		Long:    "Generates the Graph of the changes from OLD to NEW, which can be TFStates, HCL or Plans. The Nodes are matched by the Canonical and the Edges by the Nodes they connect, the added ones are displayed in green, the removed ones in red, the changed ones in orange and the rest in grey",
+		Example: "inframap diff old.tfstate new.tfstate\ninframap diff ./main/ ./feature/ --printer json",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// The OLD and NEW are always paths
+			if backendHTTP != "" || tfeWorkspace != "" {
+				return errors.New("--backend-http and --tfe-workspace cannot be used with diff")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkGroupsPrinter()
+			if err != nil {
+				return err
+			}
+
+			opt, err := generateOptions()
+			if err != nil {
+				return err
+			}
+
+			a, err := generateFromPath(args[0], opt)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+
+			b, err := generateFromPath(args[1], opt)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[1], err)
+			}
+
+			c, err := graph.Diff(a, b)
+			if err != nil {
+				return err
+			}
+
+			p, err := factory.Get(printerType)
+			if err != nil {
+				return err
+			}
+
+			popt := printerOptions(nil)
+			popt.Diff = true
+
+			return p.Print(c.Graph, popt, os.Stdout)
+		},
+	}
+)
+
+func init() {
+	diffCmd.Flags().StringVar(&printerType, "printer", "dot", fmt.Sprintf("Type of printer to use for the output, the changes are displayed on the 'dot' and 'json' ones. Supported ones are: %s", strings.Join(printer.TypeStrings(), ",")))
+	diffCmd.Flags().BoolVar(&showIcons, "show-icons", true, "Toggle the icons on the printed graph")
+	diffCmd.Flags().BoolVar(&alternativeNodeNames, "alternative-node-names", false, "Whether to try reading node names from tags, labels and other sources instead of using the canonical names")
+	addGenerateFlags(diffCmd)
+}
+
+// generateFromPath generates the Graph of the file or directory p,
+// the type is the one forced with the flags or guessed from the content
+func generateFromPath(p string, opt generate.Options) (*graph.Graph, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only HCL can used with dirs
+	if fi.IsDir() {
+		g, _, err := generate.FromHCL(afero.NewOsFs(), p, opt)
+		return g, err
+	}
+
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	isHCL, isTFState, isPlan := hcl, tfstate, tfplan
+	if !isHCL && !isTFState && !isPlan {
+		isHCL, isTFState, isPlan, err = guessGenerateType(b)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", p, err)
+		}
+	}
+
+	var g *graph.Graph
+	if isTFState {
+		g, _, err = generate.FromState(b, opt)
+	} else if isPlan {
+		g, _, err = generate.FromPlan(b, opt)
+	} else {
+		g, _, err = generate.FromHCL(afero.NewOsFs(), p, opt)
+	}
+
+	return g, err
+}
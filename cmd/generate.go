@@ -2,12 +2,16 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
+	"github.com/hashicorp/terraform/configs"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/cycloidio/inframap/generate"
 	"github.com/cycloidio/inframap/graph"
@@ -24,57 +28,49 @@ var (
 	externalNodes        bool
 	descriptionFile      string
 	alternativeNodeNames bool
+	color                bool
+	clusterModules       bool
+	groups               bool
+	instances            string
+	varFiles             []string
+	remoteStates         []string
+	include              []string
+	exclude              []string
+	recursive            bool
+	outputDir            string
+	outputPattern        string
+	indexFile            string
+	focus                []string
+	depth                int
 
 	generateCmd = &cobra.Command{
-		Use:     "generate [FILE]",
+		Use:     "generate [FILE...]",
 		Short:   "Generates the Graph",
-		Long:    "Generates the Graph from TFState or HCL",
-		Example: "inframap generate state.tfstate\ncat state.tfstate | inframap generate",
-		Args:    cobra.MaximumNArgs(1),
+		Long:    "Generates the Graph from TFState, HCL or Plan",
+		Example: "inframap generate state.tfstate\ncat state.tfstate | inframap generate\nterraform show -json plan.out | inframap generate\ninframap generate network.tfstate app.tfstate\ninframap generate app.tfstate --remote-state network/terraform.tfstate=network.tfstate\ninframap generate --recursive ./stacks --output-dir ./graphs\ninframap generate state.tfstate --focus 'module.api' --depth 2",
+		Args:    cobra.ArbitraryArgs,
 		PreRunE: preRunFile,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opt := generate.Options{
-				Raw:           raw,
-				Clean:         clean,
-				Connections:   connections,
-				ExternalNodes: externalNodes,
+			err := checkGroupsPrinter()
+			if err != nil {
+				return err
 			}
 
-			var (
-				g     *graph.Graph
-				gdesc map[string]interface{}
-				err   error
-			)
-
-			if tfstate {
-				g, gdesc, err = generate.FromState(file, opt)
-			} else {
-				if len(file) == 0 {
-					g, gdesc, err = generate.FromHCL(afero.NewOsFs(), path, opt)
-				} else {
-					fs := afero.NewMemMapFs()
-					path = "module.tf"
-
-					var f afero.File
-					f, err = fs.Create(path)
-					if err != nil {
-						return err
-					}
-
-					_, err = f.Write(file)
-					if err != nil {
-						return err
-					}
+			opt, err := generateOptions()
+			if err != nil {
+				return err
+			}
 
-					err = f.Sync()
-					if err != nil {
-						return err
-					}
+			if recursive {
+				return generateRecursive(opt)
+			}
 
-					g, gdesc, err = generate.FromHCL(fs, path, opt)
-				}
+			g, gdesc, err := generateGraph(opt)
+			if err != nil {
+				return err
 			}
 
+			g, err = focusGraph(g)
 			if err != nil {
 				return err
 			}
@@ -107,11 +103,7 @@ var (
 				df.Write(b)
 			}
 
-			popt := printer.Options{
-				ShowIcons:            showIcons,
-				AlternativeNodeNames: alternativeNodeNames,
-			}
-			err = p.Print(g, popt, os.Stdout)
+			err = p.Print(g, printerOptions(gdesc), os.Stdout)
 			if err != nil {
 				return err
 			}
@@ -123,11 +115,226 @@ var (
 
 func init() {
 	generateCmd.Flags().StringVar(&printerType, "printer", "dot", fmt.Sprintf("Type of printer to use for the output. Supported ones are: %s", strings.Join(printer.TypeStrings(), ",")))
-	generateCmd.Flags().BoolVar(&raw, "raw", false, "Raw will not use any specific logic from the provider, will just display the connections between elements. It's used by default if none of the Providers is known")
-	generateCmd.Flags().BoolVar(&clean, "clean", true, "Clean will the generated graph will not have any Node that does not have a connection/edge")
-	generateCmd.Flags().BoolVar(&connections, "connections", true, "Connections will apply the logic of the provider to remove resources that are not nodes")
 	generateCmd.Flags().BoolVar(&showIcons, "show-icons", true, "Toggle the icons on the printed graph")
-	generateCmd.Flags().BoolVar(&externalNodes, "external-nodes", true, "Toggle the addition of external nodes like 'im_out' (used to show ingress connections)")
 	generateCmd.Flags().StringVar(&descriptionFile, "description-file", "", "On the given file (will be created or overwritten) we'll output the description of the returned graph, with the attributes of all the visible nodes")
 	generateCmd.Flags().BoolVar(&alternativeNodeNames, "alternative-node-names", false, "Whether to try reading node names from tags, labels and other sources instead of using the canonical names")
+	generateCmd.Flags().BoolVar(&color, "color", false, "Toggle the ANSI colors per provider on the printers that output to the terminal, like 'ascii'")
+	generateCmd.Flags().BoolVar(&clusterModules, "cluster-modules", false, "Group the nodes on clusters following the modules in which they are defined (only for the 'dot' printer)")
+	generateCmd.Flags().BoolVar(&recursive, "recursive", false, "Generates one Graph for each root module and TFState ('.tfstate') found under the DIR, they are written on the --output-dir")
+	generateCmd.Flags().StringVar(&outputDir, "output-dir", ".", "Directory in which the Graphs are written when using --recursive")
+	generateCmd.Flags().StringVar(&outputPattern, "output-pattern", "{{.Name}}.{{.Extension}}", "Template of the name of the file of each Graph when using --recursive, with the '{{.Name}}' of the stack (the path with '_' instead of '/'), the '{{.Type}}' ('hcl' or 'tfstate') and the '{{.Extension}}' of the printer")
+	generateCmd.Flags().StringVar(&indexFile, "index-file", "index.json", "File written on the --output-dir when using --recursive with the list of all the stacks and their Graphs, if empty it's not written")
+	generateCmd.Flags().StringArrayVar(&focus, "focus", nil, "Only displays the Nodes at most --depth connections away from the Nodes which Canonical matches it, '*' matches any characters and 'module.front' matches all the resources of the module. It can be repeated")
+	generateCmd.Flags().IntVar(&depth, "depth", 1, "Number of connections from the --focus Nodes to the Nodes displayed, if negative there is no limit")
+	addGenerateFlags(generateCmd)
+}
+
+// addGenerateFlags adds to the cmd the flags used
+// to generate the Graph, the ones of generateOptions
+func addGenerateFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&raw, "raw", false, "Raw will not use any specific logic from the provider, will just display the connections between elements. It's used by default if none of the Providers is known")
+	cmd.Flags().BoolVar(&clean, "clean", true, "Clean will the generated graph will not have any Node that does not have a connection/edge")
+	cmd.Flags().BoolVar(&connections, "connections", true, "Connections will apply the logic of the provider to remove resources that are not nodes")
+	cmd.Flags().BoolVar(&externalNodes, "external-nodes", true, "Toggle the addition of external nodes like 'im_out' (used to show ingress connections)")
+	cmd.Flags().BoolVar(&groups, "groups", false, "Draw the Nodes inside of the network resources that contain them, like VPCs, subnets or networks (for the 'dot', 'mermaid', 'plantuml' and 'json' printers)")
+	cmd.Flags().StringVar(&instances, "instances", generate.InstanceFirst.String(), fmt.Sprintf("How the resources with 'count' or 'for_each' are displayed (only for TFState), 'first' instance only, 'all' the instances or 'collapse' them into one Node. Supported ones are: %s", strings.Join(generate.InstanceModeStrings(), ",")))
+	cmd.Flags().StringArrayVar(&remoteStates, "remote-state", nil, "TFState used for the 'terraform_remote_state' data sources, as KEY=FILE where the KEY is the name of the data source or a value of its backend config, like the S3 'key'. It can be repeated (only for TFState)")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "Only uses the resources that match it, as '[FIELD:]PATTERN' where the FIELD is 'canonical' (default), 'type', 'module' or 'provider' and the PATTERN a glob, '*' matches any characters, or a regexp between '/', like 'type:/^aws_(lb|instance)$/'. The resources used as connections, like the security groups, are always used. It can be repeated")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Ignores the resources that match it, with the same format as --include, like 'type:aws_iam_*'. The connections through them are kept and the resources used as connections, like the security groups, are never ignored. It can be repeated")
+	cmd.Flags().StringArrayVar(&varFiles, "var-file", nil, "Files with the values of the variables ('.tfvars') used to resolve the HCL, it can be repeated and the last ones take precedence (only for HCL)")
+}
+
+// generateGraph generates the Graph from the input read
+// on preRunFile, with the generate type of it
+func generateGraph(opt generate.Options) (*graph.Graph, map[string]interface{}, error) {
+	var (
+		g     *graph.Graph
+		gdesc map[string]interface{}
+		err   error
+	)
+
+	if len(files) > 1 {
+		tfstates := make(map[string]json.RawMessage, len(files))
+		for n, f := range files {
+			tfstates[n] = f
+		}
+		g, gdesc, err = generate.FromStates(tfstates, opt)
+	} else if tfstate {
+		g, gdesc, err = generate.FromState(file, opt)
+	} else if tfplan {
+		g, gdesc, err = generate.FromPlan(file, opt)
+	} else {
+		if len(file) == 0 {
+			g, gdesc, err = generate.FromHCL(afero.NewOsFs(), path, opt)
+		} else {
+			fs := afero.NewMemMapFs()
+			path = "module.tf"
+
+			// The HCL written in JSON is only
+			// read from the '.tf.json' files
+			if json.Valid(file) {
+				path = "module.tf.json"
+			}
+
+			var f afero.File
+			f, err = fs.Create(path)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			_, err = f.Write(file)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			err = f.Sync()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			g, gdesc, err = generate.FromHCL(fs, path, opt)
+		}
+	}
+
+	return g, gdesc, err
+}
+
+// focusGraph returns the Subgraph of the g with the Nodes around the
+// ones matching the --focus, if it's not set it returns the g
+func focusGraph(g *graph.Graph) (*graph.Graph, error) {
+	if len(focus) == 0 {
+		return g, nil
+	}
+
+	cans := make([]string, 0)
+	for _, f := range focus {
+		nodes := g.FindNodes(f)
+		if len(nodes) == 0 {
+			return nil, fmt.Errorf("no Node matches the focus %q", f)
+		}
+		for _, n := range nodes {
+			cans = append(cans, n.Canonical)
+		}
+	}
+
+	return g.Subgraph(cans, depth)
+}
+
+// checkGroupsPrinter returns an error if the --groups is
+// used with a printer that can not draw the Groups
+func checkGroupsPrinter() error {
+	if !groups {
+		return nil
+	}
+
+	t, err := printer.TypeString(printerType)
+	if err != nil {
+		return fmt.Errorf("invalid printer %q: %w", printerType, err)
+	}
+
+	if !t.SupportsGroups() {
+		return fmt.Errorf("--groups cannot be used with the printer %q, only with the 'dot', 'mermaid', 'plantuml' and 'json' ones", printerType)
+	}
+
+	return nil
+}
+
+// generateOptions returns the generate.Options from the flags
+func generateOptions() (generate.Options, error) {
+	opt := generate.Options{
+		Raw:           raw,
+		Clean:         clean,
+		Connections:   connections,
+		ExternalNodes: externalNodes,
+		Groups:        groups,
+	}
+
+	var err error
+	opt.Instances, err = generate.InstanceModeString(instances)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.Variables, err = readVarFiles(varFiles)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.RemoteStates, err = readRemoteStates(remoteStates)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.Include, err = parseFilters(include)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.Exclude, err = parseFilters(exclude)
+	if err != nil {
+		return opt, err
+	}
+
+	return opt, nil
+}
+
+// printerOptions returns the printer.Options
+// from the flags with the gdesc
+func printerOptions(gdesc map[string]interface{}) printer.Options {
+	return printer.Options{
+		ShowIcons:            showIcons,
+		AlternativeNodeNames: alternativeNodeNames,
+		Description:          gdesc,
+		Color:                color,
+		ClusterModules:       clusterModules,
+	}
+}
+
+// readVarFiles reads all the variables of the files and
+// merges them, the last ones overriding the first ones
+func readVarFiles(files []string) (map[string]cty.Value, error) {
+	vars := make(map[string]cty.Value)
+	parser := configs.NewParser(afero.NewOsFs())
+	for _, f := range files {
+		vs, diags := parser.LoadValuesFile(f)
+		if diags.HasErrors() {
+			return nil, errors.New(diags.Error())
+		}
+		for k, v := range vs {
+			vars[k] = v
+		}
+	}
+	return vars, nil
+}
+
+// readRemoteStates reads the TFStates of the remotes,
+// which are defined as KEY=FILE, by the KEY
+func readRemoteStates(remotes []string) (map[string]json.RawMessage, error) {
+	res := make(map[string]json.RawMessage, len(remotes))
+	for _, r := range remotes {
+		i := strings.LastIndex(r, "=")
+		if i <= 0 || i == len(r)-1 {
+			return nil, fmt.Errorf("invalid remote state %q, it has to be KEY=FILE", r)
+		}
+
+		b, err := ioutil.ReadFile(r[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		res[r[:i]] = b
+	}
+	return res, nil
+}
+
+// parseFilters parses all the flts with generate.ParseFilter
+func parseFilters(flts []string) ([]generate.Filter, error) {
+	res := make([]generate.Filter, 0, len(flts))
+	for _, f := range flts {
+		flt, err := generate.ParseFilter(f)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, flt)
+	}
+	return res, nil
 }
@@ -26,6 +26,8 @@ var (
 				}
 
 				fmt.Println(string(s))
+			} else if tfplan {
+				return errors.New("prune does not support Plans yet")
 			} else {
 				return errors.New("prune does not support HCL yet")
 			}
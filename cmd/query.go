@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+)
+
+var (
+	queryFrom     string
+	queryTo       string
+	queryAll      bool
+	queryMaxPaths int
+	queryMaxEdges int
+
+	queryCmd = &cobra.Command{
+		Use:     "query [FILE...]",
+		Short:   "Prints the paths between Nodes of the Graph",
+		Long:    "Prints the shortest path on the Graph from each Node that matches --from to each one that matches --to, or all of them with --all, with the resources of each connection, like the security groups. The patterns match the Canonicals, '*' matches any characters and a pattern also matches the resources inside of it, like 'im_out' matches 'im_out.tcp/80->80' and 'module.front' all the resources of the module",
+		Example: "inframap query state.tfstate --from im_out --to 'aws_db_instance.*'\ninframap query state.tfstate --from aws_lb.front --to 'module.rds' --all --max-paths 10",
+		Args:    cobra.ArbitraryArgs,
+		PreRunE: preRunFile,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if queryFrom == "" || queryTo == "" {
+				return errors.New("--from and --to are required")
+			}
+
+			opt, err := generateOptions()
+			if err != nil {
+				return err
+			}
+
+			g, _, err := generateGraph(opt)
+			if err != nil {
+				return err
+			}
+
+			paths, err := queryPaths(g, queryFrom, queryTo, queryAll, queryMaxEdges, queryMaxPaths)
+			if err != nil {
+				return err
+			}
+
+			if len(paths) == 0 {
+				fmt.Fprintf(os.Stdout, "No path found from %q to %q\n", queryFrom, queryTo)
+				return nil
+			}
+
+			printPaths(os.Stdout, paths)
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	queryCmd.Flags().StringVar(&queryFrom, "from", "", "Pattern of the Canonicals of the Nodes from which the paths start, like 'im_out'")
+	queryCmd.Flags().StringVar(&queryTo, "to", "", "Pattern of the Canonicals of the Nodes in which the paths end, like 'aws_db_instance.*'")
+	queryCmd.Flags().BoolVar(&queryAll, "all", false, "Prints all the paths between each pair of Nodes instead of only the shortest one, limited by --max-paths and --max-edges")
+	queryCmd.Flags().IntVar(&queryMaxPaths, "max-paths", 100, "Maximum number of paths, the shortest ones, between each pair of Nodes with --all, if 0 there is no limit")
+	queryCmd.Flags().IntVar(&queryMaxEdges, "max-edges", 10, "Maximum number of connections of each path with --all, if 0 there is no limit")
+	addGenerateFlags(queryCmd)
+}
+
+// queryPaths returns the shortest path from each Node of the g that
+// matches the from to each one that matches the to or, with all, the
+// paths between them with at most maxEdges and up to maxPaths of them
+func queryPaths(g *graph.Graph, from, to string, all bool, maxEdges, maxPaths int) ([]graph.Path, error) {
+	srcs := g.FindNodes(from)
+	if len(srcs) == 0 {
+		return nil, fmt.Errorf("no Node matches %q", from)
+	}
+
+	tgts := g.FindNodes(to)
+	if len(tgts) == 0 {
+		return nil, fmt.Errorf("no Node matches %q", to)
+	}
+
+	res := make([]graph.Path, 0)
+	for _, s := range srcs {
+		for _, t := range tgts {
+			if s.ID == t.ID {
+				continue
+			}
+
+			if !all {
+				p, err := g.ShortestPath(s.Canonical, t.Canonical)
+				if err != nil {
+					if errors.Is(err, errcode.ErrGraphNotFoundPath) {
+						continue
+					}
+					return nil, err
+				}
+				res = append(res, p)
+				continue
+			}
+
+			ps, err := g.AllPaths(s.Canonical, t.Canonical, maxEdges, maxPaths)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, ps...)
+		}
+	}
+
+	return res, nil
+}
+
+// printPaths prints each one of the paths on the w with each
+// hop on a line followed by the Canonicals of the Edge, like:
+//
+//	im_out.tcp/80->80
+//	  -> aws_lb.front (aws_security_group.lb)
+func printPaths(w io.Writer, paths []graph.Path) {
+	for i, p := range paths {
+		if i != 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintln(w, p.Nodes[0].Canonical)
+		for j, e := range p.Edges {
+			fmt.Fprintf(w, "  -> %s", p.Nodes[j+1].Canonical)
+			if len(e.Canonicals) != 0 {
+				fmt.Fprintf(w, " (%s)", strings.Join(e.Canonicals, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/afero"
+
+	"github.com/cycloidio/inframap/generate"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/factory"
+)
+
+// stackOutput is the entry of each
+// stack written on the index file
+type stackOutput struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// outputPatternData is the data used
+// to execute the output pattern
+type outputPatternData struct {
+	Name      string
+	Type      string
+	Extension string
+}
+
+// generateRecursive generates the Graph of each stack found on
+// the path and writes it on the outputDir with the outputPattern,
+// if one stack fails the error is on the index and the rest
+// of the stacks are still generated
+func generateRecursive(opt generate.Options) error {
+	t, err := printer.TypeString(printerType)
+	if err != nil {
+		return fmt.Errorf("invalid printer %q: %w", printerType, err)
+	}
+
+	p, err := factory.Get(printerType)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("output-pattern").Option("missingkey=error").Parse(outputPattern)
+	if err != nil {
+		return fmt.Errorf("invalid output pattern: %w", err)
+	}
+
+	fs := afero.NewOsFs()
+	stacks, err := generate.FindStacks(fs, path)
+	if err != nil {
+		return err
+	}
+
+	if len(stacks) == 0 {
+		return fmt.Errorf("no root module or TFState found on %q", path)
+	}
+
+	err = os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	// The outputs are all known before generating
+	// any so none is overwritten by another stack
+	outputs, err := stackOutputs(stacks, tmpl, t.Extension())
+	if err != nil {
+		return err
+	}
+
+	index := make([]stackOutput, 0, len(stacks))
+	var failed int
+	for i, s := range stacks {
+		so := stackOutput{
+			Name:   s.Name,
+			Type:   s.Type.String(),
+			Source: s.Path,
+			Output: outputs[i],
+		}
+
+		err = s.Err
+		if err == nil {
+			err = generateStack(fs, s, opt, p, filepath.Join(outputDir, so.Output))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error on stack %q: %s\n", s.Name, err)
+			so.Output = ""
+			so.Error = err.Error()
+			failed++
+		}
+
+		index = append(index, so)
+	}
+
+	if indexFile != "" {
+		b, err := json.MarshalIndent(index, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		err = ioutil.WriteFile(filepath.Join(outputDir, indexFile), b, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d stacks failed", failed, len(stacks))
+	}
+
+	return nil
+}
+
+// stackOutputs returns the output file of each one of the stacks
+// executing the tmpl, with the ext of the printer. If two stacks
+// have the same output, like 'a/b' and 'a_b' with the default
+// pattern, it returns an error as one would overwrite the other
+func stackOutputs(stacks []generate.Stack, tmpl *template.Template, ext string) ([]string, error) {
+	res := make([]string, 0, len(stacks))
+
+	// outputs holds the output -> Name of the stack
+	outputs := make(map[string]string, len(stacks))
+	for _, s := range stacks {
+		var buff bytes.Buffer
+		err := tmpl.Execute(&buff, outputPatternData{
+			Name:      strings.ReplaceAll(s.Name, "/", "_"),
+			Type:      s.Type.String(),
+			Extension: ext,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid output pattern: %w", err)
+		}
+
+		o := buff.String()
+		if n, ok := outputs[filepath.Clean(o)]; ok {
+			return nil, fmt.Errorf("the stacks %q and %q have the same output %q, use another --output-pattern", n, s.Name, o)
+		}
+		outputs[filepath.Clean(o)] = s.Name
+
+		res = append(res, o)
+	}
+
+	return res, nil
+}
+
+// generateStack generates the Graph of the
+// stack s and prints it with p on the out
+func generateStack(fs afero.Fs, s generate.Stack, opt generate.Options, p printer.Printer, out string) error {
+	var (
+		g     *graph.Graph
+		gdesc map[string]interface{}
+		err   error
+	)
+
+	switch s.Type {
+	case generate.StackTFState:
+		var b []byte
+		b, err = afero.ReadFile(fs, s.Path)
+		if err != nil {
+			return err
+		}
+		g, gdesc, err = generate.FromState(b, opt)
+	default:
+		g, gdesc, err = generate.FromHCL(fs, s.Path, opt)
+	}
+	if err != nil {
+		return err
+	}
+
+	g, err = focusGraph(g)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(out), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return p.Print(g, printerOptions(gdesc), f)
+}
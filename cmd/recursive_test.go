@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/generate"
+)
+
+func TestStackOutputs(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		tmpl := template.Must(template.New("output-pattern").Parse("{{.Name}}.{{.Extension}}"))
+		outputs, err := stackOutputs([]generate.Stack{
+			generate.Stack{Name: "network/prod", Type: generate.StackHCL},
+			generate.Stack{Name: "network/terraform", Type: generate.StackTFState},
+		}, tmpl, "dot")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"network_prod.dot", "network_terraform.dot"}, outputs)
+	})
+	t.Run("ErrSameOutput", func(t *testing.T) {
+		tmpl := template.Must(template.New("output-pattern").Parse("{{.Name}}.{{.Extension}}"))
+		_, err := stackOutputs([]generate.Stack{
+			generate.Stack{Name: "a/b", Type: generate.StackHCL},
+			generate.Stack{Name: "a_b", Type: generate.StackHCL},
+		}, tmpl, "dot")
+		assert.Error(t, err)
+
+		// With the Type on the pattern they are different
+		tmpl = template.Must(template.New("output-pattern").Parse("{{.Name}}.{{.Type}}.{{.Extension}}"))
+		_, err = stackOutputs([]generate.Stack{
+			generate.Stack{Name: "network", Type: generate.StackHCL},
+			generate.Stack{Name: "network", Type: generate.StackTFState},
+		}, tmpl, "dot")
+		assert.NoError(t, err)
+	})
+}
@@ -3,18 +3,34 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cycloidio/inframap/backend"
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/generate"
 )
 
 var (
 	hcl     bool
 	tfstate bool
+	tfplan  bool
 	file    []byte
 	path    string
 
+	// files holds the content of each file, by the
+	// name of it, when more than one is given
+	files map[string][]byte
+
+	backendHTTP  string
+	tfeWorkspace string
+	tfeHostname  string
+
 	rootCmd = &cobra.Command{
 		Use:   "inframap",
 		Short: "Reads the TFState or HCL to generate a Graphical view",
@@ -27,13 +43,67 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
-// preRunFile checks where the input is, ARGS or STDIN
-// also checks if --hcl or --tfstate are setted as one of
-// them is required
+// preRunFile checks where the input is, ARGS, STDIN or
+// a remote backend, also checks if --hcl or --tfstate
+// are setted as one of them is required
 func preRunFile(cmd *cobra.Command, args []string) error {
 	var err error
 
-	if len(args) == 1 {
+	if backendHTTP != "" || tfeWorkspace != "" {
+		if len(args) != 0 {
+			return errors.New("no FILE can be used with --backend-http or --tfe-workspace")
+		}
+
+		file, err = readBackend()
+		if err != nil {
+			return err
+		}
+	} else if recursive {
+		if len(args) != 1 {
+			return errors.New("one DIR is required with --recursive")
+		}
+
+		path = args[0]
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		if !fi.IsDir() {
+			return fmt.Errorf("%q is not a directory, it's required with --recursive", path)
+		}
+
+		// Each stack uses the parser of its type
+		return nil
+	} else if len(args) > 1 {
+		// Multiple files can only be TFStates
+		// that are merged into one Graph
+		if hcl || tfplan {
+			return errors.New("multiple FILEs can only be TFStates, they cannot be used with --hcl or --tfplan")
+		}
+		tfstate = true
+
+		files = make(map[string][]byte)
+		for _, p := range args {
+			n := stateName(p)
+			if _, ok := files[n]; ok {
+				return fmt.Errorf("the name %q of the file %q is already used by another file", n, p)
+			}
+
+			files[n], err = ioutil.ReadFile(p)
+			if err != nil {
+				return err
+			}
+
+			_, isState, _, err := guessGenerateType(files[n])
+			if err != nil || !isState {
+				return fmt.Errorf("the file %q is not a TFState, multiple FILEs can only be TFStates", p)
+			}
+		}
+
+		return nil
+	} else if len(args) == 1 {
 		path = args[0]
 
 		fi, err := os.Stat(path)
@@ -50,6 +120,7 @@ func preRunFile(cmd *cobra.Command, args []string) error {
 			// Only HCL can used with dirs
 			hcl = true
 			tfstate = false
+			tfplan = false
 		}
 	} else {
 		fi, err := os.Stdin.Stat()
@@ -68,37 +139,138 @@ func preRunFile(cmd *cobra.Command, args []string) error {
 
 	}
 
-	setGenerateType(file)
+	return setGenerateType(file)
+}
+
+// readBackend downloads the TFState from the backend set
+// with --backend-http or --tfe-workspace, the credentials
+// are read from the same environment variables Terraform uses
+func readBackend() ([]byte, error) {
+	if backendHTTP != "" {
+		return backend.HTTP(backendHTTP, os.Getenv("TF_HTTP_USERNAME"), os.Getenv("TF_HTTP_PASSWORD"))
+	}
+
+	org, ws, err := backend.ParseTFEWorkspace(tfeWorkspace)
+	if err != nil {
+		return nil, err
+	}
 
-	return err
+	// The same host is used for the requests
+	// and to read the token of the TF_TOKEN_*
+	h, err := backend.ParseTFEHostname(tfeHostname)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.TFE(h, org, ws, tfeToken(h))
+}
+
+// tfeToken returns the token for the hostname from the
+// TFE_TOKEN or from the TF_TOKEN_<hostname> in which the
+// '.' are '_' and the '-' are '__', like TF_TOKEN_app_terraform_io
+func tfeToken(hostname string) string {
+	if t := os.Getenv("TFE_TOKEN"); t != "" {
+		return t
+	}
+
+	h := strings.NewReplacer("-", "__", ".", "_").Replace(hostname)
+	return os.Getenv("TF_TOKEN_" + h)
+}
+
+// stateName returns the name used to namespace the
+// resources of the TFState on the path p, which is
+// the file name without extension or the directory
+// name if it's the default one 'terraform.tfstate'
+func stateName(p string) string {
+	n := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	if n == "terraform" {
+		n = filepath.Base(filepath.Dir(p))
+	}
+
+	// The '.' is the separator of the Canonicals
+	return strings.ReplaceAll(n, ".", "_")
 }
 
 // setGenerateType will try to guess the file content by first parsing it in JSON
-// and if it fails fallback to HCL.
-// If any of the flags --hcl or --tfstate are set it'll do nothing and use those
+// and if it fails fallback to HCL. If it has 'format_version' and 'planned_values'
+// it's a plan (terraform show -json plan), if it has 'version' or 'format_version'
+// it's a TFState, the statefile or the output of 'terraform show -json', and if it
+// only has HCL blocks, like 'resource', it's HCL but written in JSON (.tf.json).
+// Any other JSON returns errcode.ErrUnsupportedInput.
+// If any of the flags --hcl, --tfstate or --tfplan are set it'll do nothing and use those
 // directly as they are setted by the user
-func setGenerateType(b []byte) {
-	if hcl || tfstate {
-		return
+func setGenerateType(b []byte) error {
+	if hcl || tfstate || tfplan {
+		return nil
 	}
 
+	var err error
+	hcl, tfstate, tfplan, err = guessGenerateType(b)
+
+	return err
+}
+
+// hclJSONBlocks are the top level keys
+// of the HCL written in JSON (.tf.json)
+var hclJSONBlocks = map[string]struct{}{
+	"check":     struct{}{},
+	"data":      struct{}{},
+	"import":    struct{}{},
+	"locals":    struct{}{},
+	"module":    struct{}{},
+	"moved":     struct{}{},
+	"output":    struct{}{},
+	"provider":  struct{}{},
+	"removed":   struct{}{},
+	"resource":  struct{}{},
+	"terraform": struct{}{},
+	"variable":  struct{}{},
+	"//":        struct{}{},
+}
+
+// guessGenerateType returns if the b is HCL, TFState
+// or plan, following the logic of setGenerateType
+func guessGenerateType(b []byte) (bool, bool, bool, error) {
 	var aux map[string]interface{}
 	if err := json.Unmarshal(b, &aux); err != nil {
-		hcl = true
-		tfstate = false
-	} else {
-		hcl = false
-		tfstate = true
+		return true, false, false, nil
+	}
+
+	if generate.IsPlan(b) {
+		return false, false, true, nil
+	}
+
+	_, vok := aux["version"]
+	_, fok := aux["format_version"]
+	if vok || fok {
+		return false, true, false, nil
+	}
+
+	for k := range aux {
+		if _, ok := hclJSONBlocks[k]; !ok {
+			return false, false, false, fmt.Errorf("unknown key %q: %w", k, errcode.ErrUnsupportedInput)
+		}
 	}
+	if len(aux) == 0 {
+		return false, false, false, errcode.ErrUnsupportedInput
+	}
+
+	return true, false, false, nil
 }
 
 func init() {
 	rootCmd.AddCommand(
 		generateCmd,
+		diffCmd,
+		queryCmd,
 		pruneCmd,
 		versionCmd,
 	)
 
 	rootCmd.PersistentFlags().BoolVar(&hcl, "hcl", false, "Forces to use HCL parser")
 	rootCmd.PersistentFlags().BoolVar(&tfstate, "tfstate", false, "Forces to use TFState parser")
+	rootCmd.PersistentFlags().StringVar(&backendHTTP, "backend-http", "", "Downloads the TFState from the address of an HTTP backend, with the basic auth from TF_HTTP_USERNAME and TF_HTTP_PASSWORD")
+	rootCmd.PersistentFlags().StringVar(&tfeWorkspace, "tfe-workspace", "", "Downloads the current TFState of the Terraform Cloud/Enterprise workspace, as ORGANIZATION/WORKSPACE, with the token from TFE_TOKEN or TF_TOKEN_<hostname>")
+	rootCmd.PersistentFlags().StringVar(&tfeHostname, "tfe-hostname", backend.DefaultTFEHostname, "Hostname of the Terraform Enterprise used with --tfe-workspace, without scheme nor port like 'tfe.example.com'")
+	rootCmd.PersistentFlags().BoolVar(&tfplan, "tfplan", false, "Forces to use the Plan parser, for the output of 'terraform show -json plan'")
 }
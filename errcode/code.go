@@ -18,6 +18,12 @@ var (
 	ErrGraphAlreadyExistsNodeID      = errors.New("graph node ID already exists")
 	ErrGraphNotFoundNode             = errors.New("graph node not found")
 	ErrGraphRequiredEdgeBetweenNodes = errors.New("graph requires edge between nodes")
+	ErrGraphRequiredGroupCanonical   = errors.New("graph group canonical is required")
+	ErrGraphRequiredGroupID          = errors.New("graph group ID is required")
+	ErrGraphAlreadyExistsGroupID     = errors.New("graph group ID already exists")
+	ErrGraphNotFoundGroup            = errors.New("graph group not found")
+	ErrGraphNotFoundPath             = errors.New("graph path not found")
+	ErrGraphCycle                    = errors.New("graph has a cycle")
 
 	ErrProviderNotFoundResource   = errors.New("provider resource not found")
 	ErrProviderNotFoundDataSource = errors.New("provider data source not found")
@@ -27,7 +33,22 @@ var (
 	ErrInvalidTFStateVersion               = errors.New("invalid Terraform State version, we only support version 3 and 4")
 	ErrInvalidTFStateFileMissingResourceID = errors.New("invalid Terraform State file, a resource is missing the attributes.id")
 
+	ErrInvalidPlan = errors.New("invalid Terraform Plan, it requires the 'format_version' and 'planned_values'")
+
+	ErrInvalidFilter = errors.New("invalid filter, it has to be '[FIELD:]PATTERN' with FIELD 'canonical', 'type', 'module' or 'provider'")
+
 	ErrPrinterNotFound = errors.New("printer not found")
 
+	ErrUnsupportedInput = errors.New("unsupported input, it has to be a TFState, a plan or HCL")
+
+	// Deprecated: the HCL written in JSON (.tf.json) is
+	// supported so this error is no longer returned
 	ErrGenerateFromJSON = errors.New("we do not support JSON HCL")
+
+	ErrBackendNotFoundState       = errors.New("backend has no state")
+	ErrBackendInvalidStatus       = errors.New("backend returned an invalid status code")
+	ErrBackendInvalidResponse     = errors.New("backend returned an invalid response")
+	ErrBackendInvalidTFEWorkspace = errors.New("invalid Terraform Cloud workspace")
+	ErrBackendInvalidTFEHostname  = errors.New("invalid Terraform Cloud hostname")
+	ErrBackendRequiredToken       = errors.New("backend token is required")
 )
@@ -0,0 +1,178 @@
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/provider"
+)
+
+// FilterField defines the field of the
+// resources a Filter is matched against
+type FilterField int
+
+//go:generate ./../bin/enumer -type=FilterField -transform=lower -trimprefix=Filter -output=filter_field_string.go
+
+// List of all FilterFields
+const (
+	// FilterCanonical matches the Canonical of the resource,
+	// like 'aws_lb.front' or 'module.front.aws_lb.front'
+	FilterCanonical FilterField = iota
+
+	// FilterType matches the type of the resource, like 'aws_lb'
+	FilterType
+
+	// FilterModule matches the path of the module of the
+	// resource, like 'module.front', the root one is empty
+	FilterModule
+
+	// FilterProvider matches the provider.Type
+	// of the resource, like 'aws'
+	FilterProvider
+)
+
+// Filter selects the resources which Field matches the Pattern
+type Filter struct {
+	Field FilterField
+
+	// Pattern is a glob, in which the '*' matches any characters
+	// and the '?' only one, or a regexp if it's between '/'
+	// like '/^aws_iam_/'. On the Canonical and the Module the
+	// glob also matches the ones inside of it, so 'module.front'
+	// matches all the modules called from it
+	Pattern string
+
+	re *regexp.Regexp
+}
+
+// ParseFilter parses the f, with the format '[FIELD:]PATTERN', to a Filter. The
+// FIELD is any of the FilterFieldStrings, by default the FilterCanonical, like
+// 'type:aws_iam_*', 'module:module.front' or 'provider:/^(aws|google)$/'. If the
+// text before the ':' is not a FIELD all the f is the PATTERN of the Canonical
+func ParseFilter(f string) (Filter, error) {
+	flt := Filter{
+		Field:   FilterCanonical,
+		Pattern: f,
+	}
+
+	// The text before the first ':' is only the FIELD if it's one
+	// of them, if not it's part of the PATTERN, like the regexps
+	// '/^(?:aws_iam)/' or '/[[:alpha:]]/'
+	if i := strings.Index(f, ":"); i != -1 && !strings.HasPrefix(f, "/") {
+		if field, err := FilterFieldString(f[:i]); err == nil {
+			flt.Field = field
+			flt.Pattern = f[i+1:]
+		}
+	}
+
+	if len(flt.Pattern) > 1 && strings.HasPrefix(flt.Pattern, "/") && strings.HasSuffix(flt.Pattern, "/") {
+		re, err := regexp.Compile(flt.Pattern[1 : len(flt.Pattern)-1])
+		if err != nil {
+			return flt, fmt.Errorf("filter %q: %s: %w", f, err, errcode.ErrInvalidFilter)
+		}
+		flt.re = re
+		return flt, nil
+	}
+
+	re := regexp.QuoteMeta(flt.Pattern)
+	re = strings.ReplaceAll(re, `\*`, `.*`)
+	re = strings.ReplaceAll(re, `\?`, `.`)
+	if flt.Field == FilterCanonical || flt.Field == FilterModule {
+		// As graph.MatchCanonical but from the start, so
+		// 'aws_lb.*' only matches the ones of the root module
+		flt.re = regexp.MustCompile(`^(?:` + re + `)(?:\..*)?$`)
+	} else {
+		flt.re = regexp.MustCompile(`^(?:` + re + `)$`)
+	}
+
+	return flt, nil
+}
+
+// Match checks if the resource with the Canonical can, on
+// the module path mod and of the type rs from the Provider
+// pv, matches the f
+func (f Filter) Match(can, mod, rs string, pv provider.Type) bool {
+	var v string
+	switch f.Field {
+	case FilterCanonical:
+		v = can
+	case FilterType:
+		v = rs
+	case FilterModule:
+		v = mod
+	case FilterProvider:
+		v = pv.String()
+	}
+
+	return f.re.MatchString(v)
+}
+
+// filtered checks if the resource with the Canonical can, on the module
+// path mod and of the type rs from the Provider pv, has to be ignored
+// following the Include and Exclude of the opt. The can is namespaced
+// with the name of the TFState. The mod is not taken from the can as
+// the keys of the modules, like 'module.front["a.b"]', can have '.'.
+// The Edges of the pv, like the security groups, are never ignored
+// as the logic of the pv needs them to connect the Nodes
+func (opt Options) filtered(name, mod, can, rs string, pv provider.Provider) bool {
+	if pv.IsEdge(rs) && !pv.IsNode(rs) {
+		return false
+	}
+
+	pt := pv.Type()
+	can = prefixWithState(name, can)
+
+	if len(opt.Include) != 0 {
+		included := false
+		for _, f := range opt.Include {
+			if f.Match(can, mod, rs, pt) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return true
+		}
+	}
+
+	for _, f := range opt.Exclude {
+		if f.Match(can, mod, rs, pt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// followExcluded returns the deps replacing the Canonicals of the
+// excluded resources with their own dependencies, recursively, so the
+// connections through them are kept: if A depends on an excluded
+// B that depends on C, A depends on C
+func followExcluded(deps []string, excluded map[string][]string) []string {
+	if len(excluded) == 0 {
+		return deps
+	}
+
+	res := make([]string, 0, len(deps))
+	visited := make(map[string]struct{})
+	queue := append([]string{}, deps...)
+	for len(queue) != 0 {
+		d := queue[0]
+		queue = queue[1:]
+
+		if _, ok := visited[d]; ok {
+			continue
+		}
+		visited[d] = struct{}{}
+
+		if edeps, ok := excluded[d]; ok {
+			queue = append(queue, edeps...)
+			continue
+		}
+		res = append(res, d)
+	}
+
+	return res
+}
@@ -0,0 +1,73 @@
+package generate_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/generate"
+	"github.com/cycloidio/inframap/provider"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		tests := []struct {
+			Filter  string
+			Field   generate.FilterField
+			Pattern string
+		}{
+			{Filter: "aws_lb.front", Field: generate.FilterCanonical, Pattern: "aws_lb.front"},
+			{Filter: "canonical:aws_lb.*", Field: generate.FilterCanonical, Pattern: "aws_lb.*"},
+			{Filter: "type:aws_iam_*", Field: generate.FilterType, Pattern: "aws_iam_*"},
+			{Filter: "module:module.front", Field: generate.FilterModule, Pattern: "module.front"},
+			{Filter: "provider:/^(aws|google)$/", Field: generate.FilterProvider, Pattern: "/^(aws|google)$/"},
+			{Filter: "/^(?:aws_iam)/", Field: generate.FilterCanonical, Pattern: "/^(?:aws_iam)/"},
+			{Filter: "/[[:alpha:]]/", Field: generate.FilterCanonical, Pattern: "/[[:alpha:]]/"},
+			{Filter: "/type:aws/", Field: generate.FilterCanonical, Pattern: "/type:aws/"},
+			{Filter: "name:aws_lb", Field: generate.FilterCanonical, Pattern: "name:aws_lb"},
+		}
+		for _, tt := range tests {
+			f, err := generate.ParseFilter(tt.Filter)
+			require.NoError(t, err, tt.Filter)
+			assert.Equal(t, tt.Field, f.Field, tt.Filter)
+			assert.Equal(t, tt.Pattern, f.Pattern, tt.Filter)
+		}
+	})
+	t.Run("ErrInvalidFilter", func(t *testing.T) {
+		for _, f := range []string{"type:/aws_(lb/", "/aws_(lb/"} {
+			_, err := generate.ParseFilter(f)
+			assert.True(t, errors.Is(err, errcode.ErrInvalidFilter), f)
+		}
+	})
+}
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		Filter string
+		Match  bool
+	}{
+		{Filter: "module.front.aws_lb.front", Match: true},
+		{Filter: "module.front", Match: true},
+		{Filter: "*.aws_lb.*", Match: true},
+		{Filter: "aws_lb.front", Match: false},
+		{Filter: "/aws_lb\\.fr/", Match: true},
+		{Filter: "type:aws_lb", Match: true},
+		{Filter: "type:aws_*", Match: true},
+		{Filter: "type:aws", Match: false},
+		{Filter: "module:module.front", Match: true},
+		{Filter: "module:module.fro?t", Match: true},
+		{Filter: "module:module.back", Match: false},
+		{Filter: "provider:aws", Match: true},
+		{Filter: "provider:/^(google|azurerm)$/", Match: false},
+		{Filter: "/^(?:module\\.front)\\.aws_lb/", Match: true},
+		{Filter: "/[[:alpha:]]+\\.[[:alpha:]]+$/", Match: true},
+	}
+	for _, tt := range tests {
+		f, err := generate.ParseFilter(tt.Filter)
+		require.NoError(t, err, tt.Filter)
+		assert.Equal(t, tt.Match, f.Match("module.front.aws_lb.front", "module.front", "aws_lb", provider.AWS), tt.Filter)
+	}
+}
@@ -0,0 +1,151 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+)
+
+// assignGroups sets the Parent of each graph.Group and the Group of
+// each graph.Node by checking the values of the GroupAttributes of the
+// Provider on the configuration of each one.
+// The cfg holds the config of the Nodes and the groupCfg the config
+// of the Groups, both as ID -> Attrs
+func assignGroups(g *graph.Graph, cfg, groupCfg map[string]map[string]interface{}, opt Options) error {
+	// First we set the Parents so then when assigning the
+	// Nodes we know which is the deepest Group
+	for _, gr := range g.Groups {
+		pv, _, err := getProviderAndResource(gr.Canonical, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range matchGroups(g, groupCfg[gr.ID], pv.GroupAttributes()) {
+			// A Group can not be contained on itself
+			// or on one of the Groups it contains
+			if p.ID == gr.ID || isGroupAncestor(g, gr.ID, p) {
+				continue
+			}
+			gr.Parent = p.ID
+			break
+		}
+	}
+
+	for _, n := range g.Nodes {
+		err := assignNodeGroup(g, n, cfg[n.ID], opt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assignNodeGroup sets to the n, which has the cfg, the
+// deepest Group of the g it references, if any
+func assignNodeGroup(g *graph.Graph, n *graph.Node, cfg map[string]interface{}, opt Options) error {
+	pv, _, err := getProviderAndResource(n.Canonical, opt)
+	if err != nil {
+		return err
+	}
+
+	bestDepth := -1
+	for _, gr := range matchGroups(g, cfg, pv.GroupAttributes()) {
+		if d := groupDepth(g, gr); d > bestDepth {
+			bestDepth = d
+			n.Group = gr.ID
+		}
+	}
+
+	return nil
+}
+
+// matchGroups returns all the Groups of g referenced
+// on the attrs of the cfg
+func matchGroups(g *graph.Graph, cfg map[string]interface{}, attrs []string) []*graph.Group {
+	res := make([]*graph.Group, 0)
+	if len(cfg) == 0 {
+		return res
+	}
+
+	for _, attr := range attrs {
+		for _, v := range attributeValues(cfg, strings.Split(attr, ".")) {
+			for _, gr := range g.Groups {
+				if isGroupReference(gr, v) {
+					res = append(res, gr)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// attributeValues returns all the string values found
+// following the keys on v, if any of the values on the
+// way is a list all the elements of it are checked
+func attributeValues(v interface{}, keys []string) []string {
+	switch vv := v.(type) {
+	case string:
+		if len(keys) == 0 && vv != "" {
+			return []string{vv}
+		}
+	case []interface{}:
+		res := make([]string, 0)
+		for _, i := range vv {
+			res = append(res, attributeValues(i, keys)...)
+		}
+		return res
+	case map[string]interface{}:
+		if len(keys) != 0 {
+			return attributeValues(vv[keys[0]], keys[1:])
+		}
+	}
+	return nil
+}
+
+// isGroupReference checks if the v is a reference to the gr,
+// which on HCL is an interpolation like '${aws_vpc.main.id}'
+// and on the TFState is the TFID (or an URL ending with it)
+// or the Name of it
+func isGroupReference(gr *graph.Group, v string) bool {
+	if res := reVariable.FindStringSubmatch(v); res != nil {
+		return fmt.Sprintf("%s.%s", res[1], res[2]) == gr.Canonical
+	}
+
+	if gr.TFID != "" && (v == gr.TFID || strings.HasSuffix(v, "/"+gr.TFID)) {
+		return true
+	}
+
+	return gr.Name != "" && v == gr.Name
+}
+
+// isGroupAncestor checks if the Group with ID gID
+// is one of the Parents of the gr
+func isGroupAncestor(g *graph.Graph, gID string, gr *graph.Group) bool {
+	for p := gr.Parent; p != ""; {
+		if p == gID {
+			return true
+		}
+		pgr, err := g.GetGroupByID(p)
+		if err != nil {
+			return false
+		}
+		p = pgr.Parent
+	}
+	return false
+}
+
+// groupDepth returns the number of Parents of the gr
+func groupDepth(g *graph.Graph, gr *graph.Group) int {
+	var d int
+	for p := gr.Parent; p != ""; d++ {
+		pgr, err := g.GetGroupByID(p)
+		if err != nil {
+			break
+		}
+		p = pgr.Parent
+	}
+	return d
+}
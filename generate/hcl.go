@@ -3,7 +3,7 @@ package generate
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"path/filepath"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -45,100 +45,153 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, map[string]in
 		return nil, nil, errors.New(diags.Error())
 	}
 
+	// The SourceDir is used to find the
+	// modules called with a relative path
+	if mod.SourceDir == "" {
+		mod.SourceDir = filepath.Dir(path)
+	}
+
+	config, err := loadHCLConfig(parser, mod)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// nodeCanIDs holds as key the `aws_alb.front` (graph.Node.Canonical)
 	// and as value the UUID (graph.Node.ID) we give to it
 	nodeCanIDs := make(map[string][]string)
 
 	// nodeIDEdges holds as key the UUID (graph.Node.ID) and as value
-	// all the edges it has, in this case it's the `depends_on` values
-	// that we find on the TFState
+	// all the edges it has, in this case it's the Canonicals of the
+	// resources referenced on the HCL
 	nodeIDEdges := make(map[string][]string)
 
 	// resourcesRawConfig holds the actual configuration of each element
 	// it's represented as: graph.Node.Canonical -> Attrs
 	resourcesRawConfig := make(map[string]map[string]interface{})
 
+	// groupCfg holds the actual configuration of each Group
+	// it's represented as: graph.Group.ID -> Attrs
+	groupCfg := make(map[string]map[string]interface{})
+
+	// excluded holds the Canonical -> Canonicals referenced by the
+	// resources ignored by the Options.Include and Exclude
+	excluded := make(map[string][]string)
+
+	// jsonSrcs holds the content of the JSON files
+	// it's represented as: File Name -> Content
+	jsonSrcs := make(map[string][]byte)
+
 	if !opt.Raw {
-		opt, err = checkHCLProviders(mod, opt)
+		opt, err = checkHCLProviders(config, opt)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
 
-	for rk, rv := range mod.ManagedResources {
-		pv, rs, err := getProviderAndResource(rk, opt)
-		if err != nil {
-			if errors.Is(err, errcode.ErrProviderNotFound) {
+	for _, c := range moduleConfigs(config) {
+		for rk, rv := range c.Module.ManagedResources {
+			can := prefixWithModule(c.Path.String(), rk)
+			pv, rs, err := getProviderAndResource(can, opt)
+			if err != nil {
+				if errors.Is(err, errcode.ErrProviderNotFound) {
+					continue
+				}
+				return nil, nil, err
+			}
+
+			isGroup := opt.Groups && pv.IsGroup(rs)
+
+			// If it's not a Node, Edge or Group we ignore it
+			if !pv.IsNode(rs) && !pv.IsEdge(rs) && !isGroup {
 				continue
 			}
-			return nil, nil, err
-		}
 
-		// If it's not a Node or Edge we ignore it
-		if !pv.IsNode(rs) && !pv.IsEdge(rs) {
-			continue
-		}
+			res, err := pv.Resource(rs)
+			if err != nil {
+				return nil, nil, err
+			}
 
-		res, err := pv.Resource(rs)
-		if err != nil {
-			return nil, nil, err
-		}
-		n := &graph.Node{
-			ID:        uuid.NewV4().String(),
-			Canonical: rk,
-			Resource:  *res,
-		}
+			links, cfg, err := getResourceLinksAndConfig(fs, rv.Config, jsonSrcs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("resource %q: %w", can, err)
+			}
+
+			if opt.filtered("", c.Path.String(), can, rs, pv) {
+				// Only the references are kept so the
+				// resources can be connected through it
+				if !isGroup {
+					for _, refs := range links {
+						for _, ref := range refs {
+							for _, r := range resolveReference(c, ref) {
+								excluded[can] = append(excluded[can], r.Canonical)
+							}
+						}
+					}
+				}
+				continue
+			}
+
+			// The references are relative to the module so they
+			// are resolved to the Canonicals or to their values
+			cfg = rewriteReferences(c, cfg, opt.Variables).(map[string]interface{})
+
+			if isGroup {
+				gr := &graph.Group{
+					ID:        uuid.NewV4().String(),
+					Canonical: can,
+					Name:      extractResourceName(cfg),
+					Resource:  *res,
+				}
+
+				err = g.AddGroup(gr)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				groupCfg[gr.ID] = cfg
+				continue
+			}
+			n := &graph.Node{
+				ID:        uuid.NewV4().String(),
+				Canonical: can,
+				Resource:  *res,
+			}
 
-		nodeCanIDs[n.Canonical] = append(nodeCanIDs[n.Canonical], n.ID)
+			nodeCanIDs[n.Canonical] = append(nodeCanIDs[n.Canonical], n.ID)
 
-		var links map[string][]string
-		body, ok := rv.Config.(*hclsyntax.Body)
-		if ok {
-			links = getBodyLinks(body)
-			cfg := getBodyJSON(body)
-			cfg[provider.HCLCanonicalKey] = rk
+			cfg[provider.HCLCanonicalKey] = can
 			resourcesRawConfig[n.ID] = cfg
-			nodeName := extractResourceName(resourcesRawConfig[n.ID])
-			n.Name = nodeName
-		} else {
-			// If it's not a hclsyntax.Body normally
-			// means it's from a JSON file, the only
-			// way to work with them is with rv.Config.JustAttributes()
-			// and manually deal with them. For what I've tested
-			// the Blocks (so egess an ingress for example) do not
-			// work and fail so we should find a workaround.
-			return nil, nil, errcode.ErrGenerateFromJSON
-		}
+			n.Name = extractResourceName(cfg)
 
-		err = g.AddNode(n)
-		if err != nil {
-			return nil, nil, err
-		}
+			err = g.AddNode(n)
+			if err != nil {
+				return nil, nil, err
+			}
 
-		for _, resources := range links {
-			nodeIDEdges[n.ID] = append(nodeIDEdges[n.ID], resources...)
+			for _, refs := range links {
+				for _, ref := range refs {
+					for _, r := range resolveReference(c, ref) {
+						nodeIDEdges[n.ID] = append(nodeIDEdges[n.ID], r.Canonical)
+					}
+				}
+			}
 		}
 	}
 
-	for nid, resources := range nodeIDEdges {
-		for _, rkattr := range resources {
-			keys := strings.Split(rkattr, ".")
-
-			rk := rkattr
-			// If the values are like 'aws_security_group.front.id'
-			// and we do not need the 'id' as it's not on the key
-			// so we subtract it from the key
-			// But it's not always the case
-			if len(keys) == 3 {
-				rk = strings.Join(keys[:len(keys)-1], ".")
-			}
-
-			tnids, ok := nodeCanIDs[rk]
+	for nid, cans := range nodeIDEdges {
+		for _, can := range followExcluded(cans, excluded) {
+			tnids, ok := nodeCanIDs[can]
 			if !ok {
 				continue
 			}
 			tnid := tnids[0]
 
+			// It could reference itself
+			// through an excluded resource
+			if tnid == nid {
+				continue
+			}
+
 			err := g.AddEdge(&graph.Edge{
 				ID:     uuid.NewV4().String(),
 				Source: nid,
@@ -155,6 +208,13 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, map[string]in
 		}
 	}
 
+	if opt.Groups {
+		err = assignGroups(g, resourcesRawConfig, groupCfg, opt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// call the preprocess method for each
 	// TF provider in the file
 	if err := preprocess(g, resourcesRawConfig, opt); err != nil {
@@ -182,6 +242,8 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, map[string]in
 		if err != nil {
 			return nil, nil, err
 		}
+
+		g.CleanGroups()
 	}
 
 	endCfg, err := buildConfig(g, resourcesRawConfig, nodeCanIDs)
@@ -191,6 +253,30 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, map[string]in
 	return g, endCfg, nil
 }
 
+// getResourceLinksAndConfig returns the links, from getBodyLinks,
+// and the config, from getBodyJSON, of the body b. If it's not a
+// hclsyntax.Body it's from a JSON file (.tf.json) so the
+// getJSONBodyLinks and getJSONBodyConfig are used instead
+func getResourceLinksAndConfig(fs afero.Fs, b hcl.Body, jsonSrcs map[string][]byte) (map[string][]string, map[string]interface{}, error) {
+	if body, ok := b.(*hclsyntax.Body); ok {
+		return getBodyLinks(body), getBodyJSON(body), nil
+	}
+
+	// As there is no schema all the
+	// content is read as attributes
+	attrs, diags := b.JustAttributes()
+	if diags.HasErrors() {
+		return nil, nil, errors.New(diags.Error())
+	}
+
+	cfg, err := getJSONBodyConfig(fs, attrs, jsonSrcs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return getJSONBodyLinks(attrs), cfg, nil
+}
+
 // getBodyLinks gets all the variables used and the key in which
 // they where used
 func getBodyLinks(b *hclsyntax.Body) map[string][]string {
@@ -262,21 +348,23 @@ func getBodyJSON(b *hclsyntax.Body) map[string]interface{} {
 	return links
 }
 
-// checkHCLProviders checks if we support any of the Providers from f, if not it'll set
+// checkHCLProviders checks if we support any of the Providers from config and the modules it calls, if not it'll set
 // the opt.Raw to true so it can be used with Raw instead of returning an empty Graph
-func checkHCLProviders(mod *configs.Module, opt Options) (Options, error) {
-	for rk := range mod.ManagedResources {
-		_, _, err := getProviderAndResource(rk, opt)
-		if err != nil {
-			if errors.Is(err, errcode.ErrProviderNotFound) {
-				continue
+func checkHCLProviders(config *configs.Config, opt Options) (Options, error) {
+	for _, c := range moduleConfigs(config) {
+		for rk := range c.Module.ManagedResources {
+			_, _, err := getProviderAndResource(rk, opt)
+			if err != nil {
+				if errors.Is(err, errcode.ErrProviderNotFound) {
+					continue
+				}
+				return opt, err
 			}
-			return opt, err
-		}
 
-		// If we find a resource that we support the Provider
-		// then we use it
-		return opt, nil
+			// If we find a resource that we support the Provider
+			// then we use it
+			return opt, nil
+		}
 	}
 
 	// If we reach here means the we do not support the providers
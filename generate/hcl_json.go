@@ -0,0 +1,105 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/spf13/afero"
+)
+
+// getJSONBodyLinks gets all the variables used and the key in which
+// they where used from the attrs of a JSON body, the ones used
+// inside of blocks are on the key of the block
+func getJSONBodyLinks(attrs hcl.Attributes) map[string][]string {
+	links := make(map[string][]string)
+	for attrk, attrv := range attrs {
+		for _, vr := range attrv.Expr.Variables() {
+			links[attrk] = append(links[attrk], string(hclwrite.TokensForTraversal(vr).Bytes()))
+		}
+	}
+
+	return links
+}
+
+// getJSONBodyConfig gets the configuration of the attrs of a JSON body
+// by reading the JSON of each one from the file on the fs, which is cached
+// on the jsonSrcs. As the JSON has no schema, all the objects are
+// considered blocks and represented as a list of objects, which
+// is how the getBodyJSON represents the blocks, and the references
+// are left as they are written '${aws_security_group.front.id}'
+func getJSONBodyConfig(fs afero.Fs, attrs hcl.Attributes, jsonSrcs map[string][]byte) (map[string]interface{}, error) {
+	cfg := make(map[string]interface{})
+	for attrk, attrv := range attrs {
+		rng := attrv.Expr.Range()
+		src, ok := jsonSrcs[rng.Filename]
+		if !ok {
+			var err error
+			src, err = afero.ReadFile(fs, rng.Filename)
+			if err != nil {
+				return nil, err
+			}
+			jsonSrcs[rng.Filename] = src
+		}
+
+		if rng.End.Byte > len(src) {
+			return nil, fmt.Errorf("attribute %q out of the file %q", attrk, rng.Filename)
+		}
+
+		var v interface{}
+		err := json.Unmarshal(src[rng.Start.Byte:rng.End.Byte], &v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON on attribute %q: %w", attrk, err)
+		}
+
+		if v = jsonConfigValue(v); v != nil {
+			cfg[attrk] = v
+		}
+	}
+
+	return cfg, nil
+}
+
+// jsonConfigValue converts the v, decoded from JSON, to the
+// same representation the getBodyJSON has
+func jsonConfigValue(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		return []interface{}{jsonBlockConfig(vv)}
+	case []interface{}:
+		res := make([]interface{}, 0, len(vv))
+		for _, i := range vv {
+			if m, ok := i.(map[string]interface{}); ok {
+				res = append(res, jsonBlockConfig(m))
+			} else {
+				res = append(res, jsonConfigValue(i))
+			}
+		}
+		return res
+	case float64:
+		// The numbers without decimals are int
+		// as on the HCL native syntax
+		if vv == math.Trunc(vv) {
+			return int(vv)
+		}
+	}
+	return v
+}
+
+// jsonBlockConfig returns the config of the
+// block represented by the JSON object m
+func jsonBlockConfig(m map[string]interface{}) map[string]interface{} {
+	cfg := make(map[string]interface{})
+	for k, v := range m {
+		// The '//' keys are comments
+		if k == "//" {
+			continue
+		}
+		if v = jsonConfigValue(v); v != nil {
+			cfg[k] = v
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,363 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/hashicorp/terraform/configs/hcl2shim"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// hclReference is a reference to a resource
+// already resolved to its Canonical, with the
+// module prefix, and the Attribute used if any
+type hclReference struct {
+	Canonical string
+	Attribute string
+}
+
+// String returns the reference as it would be
+// written on the HCL 'aws_security_group.front.id'
+func (r hclReference) String() string {
+	if r.Attribute == "" {
+		return r.Canonical
+	}
+	return fmt.Sprintf("%s.%s", r.Canonical, r.Attribute)
+}
+
+// reInterpolation matches ${aws_security_group.front.id} or ${var.sg_id}
+var reInterpolation = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// loadHCLConfig builds the configs.Config of the mod following
+// all the module calls with a local source ('./' or '../'),
+// the other ones (registry, git ...) are ignored as
+// they are not downloaded
+func loadHCLConfig(parser *configs.Parser, mod *configs.Module) (*configs.Config, error) {
+	cfg, diags := configs.BuildConfig(mod, configs.ModuleWalkerFunc(func(req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
+		if !isLocalSourceAddr(req.SourceAddr) {
+			return nil, nil, nil
+		}
+
+		// If the module is not present, like when reading
+		// from the STDIN, it's ignored too
+		dir := filepath.Join(req.Parent.Module.SourceDir, req.SourceAddr)
+		if !parser.IsConfigDir(dir) {
+			return nil, nil, nil
+		}
+
+		m, diags := parser.LoadConfigDir(dir)
+		return m, nil, diags
+	}))
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+
+	return cfg, nil
+}
+
+// isLocalSourceAddr checks if the addr is a path
+// relative to the module calling it
+func isLocalSourceAddr(addr string) bool {
+	return strings.HasPrefix(addr, "./") || strings.HasPrefix(addr, "../")
+}
+
+// moduleConfigs returns the c and all the modules
+// it calls, recursively, sorted by the module path
+func moduleConfigs(c *configs.Config) []*configs.Config {
+	res := []*configs.Config{c}
+
+	names := make([]string, 0, len(c.Children))
+	for n := range c.Children {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		res = append(res, moduleConfigs(c.Children[n])...)
+	}
+
+	return res
+}
+
+// resolveReference returns the resources the reference ref (like
+// 'aws_security_group.front.id', 'var.sg_id', 'local.sg_id' or
+// 'module.front.sg_id') points to from the module c. The variables
+// are followed to the arguments of the module call on the parent, the
+// locals to their expression and the module outputs to the expression
+// of the output on the child module
+func resolveReference(c *configs.Config, ref string) []hclReference {
+	tr, diags := hclsyntax.ParseTraversalAbs([]byte(ref), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil
+	}
+
+	return resolveTraversal(c, tr, make(map[string]struct{}))
+}
+
+// resolveTraversal is the implementation of resolveReference, the
+// visited holds the already followed references so a cycle between
+// modules does not end on an infinite loop
+func resolveTraversal(c *configs.Config, tr hcl.Traversal, visited map[string]struct{}) []hclReference {
+	key := fmt.Sprintf("%s:%s", c.Path.String(), hclwrite.TokensForTraversal(tr).Bytes())
+	if _, ok := visited[key]; ok {
+		return nil
+	}
+	visited[key] = struct{}{}
+
+	names := traversalAttrNames(tr)
+	if len(names) < 2 {
+		return nil
+	}
+
+	switch tr.RootName() {
+	case "var":
+		if c.Parent == nil {
+			return nil
+		}
+
+		call, ok := c.Parent.Module.ModuleCalls[c.Path[len(c.Path)-1]]
+		if !ok {
+			return nil
+		}
+
+		// The Body of the call only has the arguments
+		// left as the rest have already been decoded
+		attrs, _ := call.Config.JustAttributes()
+		attr, ok := attrs[names[1]]
+		if !ok {
+			return nil
+		}
+
+		return resolveExpression(c.Parent, attr.Expr, visited)
+	case "module":
+		child, ok := c.Children[names[1]]
+		if !ok || len(names) < 3 {
+			return nil
+		}
+
+		out, ok := child.Module.Outputs[names[2]]
+		if !ok {
+			return nil
+		}
+
+		return resolveExpression(child, out.Expr, visited)
+	case "local":
+		l, ok := c.Module.Locals[names[1]]
+		if !ok {
+			return nil
+		}
+
+		return resolveExpression(c, l.Expr, visited)
+	case "data", "count", "each", "path", "self", "terraform":
+		return nil
+	}
+
+	ref := hclReference{
+		Canonical: prefixWithModule(c.Path.String(), fmt.Sprintf("%s.%s", names[0], names[1])),
+	}
+	if len(names) > 2 {
+		ref.Attribute = names[2]
+	}
+
+	return []hclReference{ref}
+}
+
+// resolveExpression returns the resources referenced by all
+// the variables of the expr on the module c
+func resolveExpression(c *configs.Config, expr hcl.Expression, visited map[string]struct{}) []hclReference {
+	res := make([]hclReference, 0)
+	for _, vr := range expr.Variables() {
+		res = append(res, resolveTraversal(c, vr, visited)...)
+	}
+	return res
+}
+
+// traversalAttrNames returns the root name and the name of all
+// the attributes of the tr, the indexes are ignored so
+// 'aws_instance.web[0].id' is 'aws_instance', 'web', 'id'
+func traversalAttrNames(tr hcl.Traversal) []string {
+	names := make([]string, 0, len(tr))
+	for _, t := range tr {
+		switch tt := t.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, tt.Name)
+		case hcl.TraverseAttr:
+			names = append(names, tt.Name)
+		}
+	}
+	return names
+}
+
+// rewriteReferences replaces all the interpolations on the values of
+// v with the resource they reference from the module c, so the
+// Providers can match them with the Canonicals of the Nodes, for
+// example '${var.sg_id}' could be '${aws_security_group.front.id}'.
+// If they do not reference only one resource but they are variables
+// or locals with a known value, from the vars or the defaults,
+// they are replaced by it. The rest are left as they are
+func rewriteReferences(c *configs.Config, v interface{}, vars map[string]cty.Value) interface{} {
+	switch vv := v.(type) {
+	case string:
+		// If the value is only the interpolation
+		// it's replaced with the value as it is
+		// so the lists or numbers keep the type
+		if m := reInterpolation.FindStringSubmatch(vv); m != nil && m[0] == vv {
+			if refs := resolveReference(c, m[1]); len(refs) == 1 {
+				return fmt.Sprintf("${%s}", refs[0])
+			}
+			if val, ok := evalReference(c, m[1], vars); ok {
+				return hcl2shim.ConfigValueFromHCL2(val)
+			}
+			return vv
+		}
+
+		return reInterpolation.ReplaceAllStringFunc(vv, func(s string) string {
+			ref := reInterpolation.FindStringSubmatch(s)[1]
+			if refs := resolveReference(c, ref); len(refs) == 1 {
+				return fmt.Sprintf("${%s}", refs[0])
+			}
+			if val, ok := evalReference(c, ref, vars); ok {
+				if sval, err := convert.Convert(val, cty.String); err == nil {
+					return sval.AsString()
+				}
+			}
+			return s
+		})
+	case []interface{}:
+		for i := range vv {
+			vv[i] = rewriteReferences(c, vv[i], vars)
+		}
+	case map[string]interface{}:
+		for k := range vv {
+			vv[k] = rewriteReferences(c, vv[k], vars)
+		}
+	}
+	return v
+}
+
+// evalReference returns the value of the reference ref (like 'var.port'
+// or 'local.cidrs') on the module c, it's only known if all the variables
+// and locals it depends on have a value. The values of the variables of the
+// root module are the vars, if not defined the default, and on the modules
+// called the arguments of the call
+func evalReference(c *configs.Config, ref string, vars map[string]cty.Value) (cty.Value, bool) {
+	tr, diags := hclsyntax.ParseTraversalAbs([]byte(ref), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+
+	ctx, ok := evalContext(c, []hcl.Traversal{tr}, vars, make(map[string]struct{}))
+	if !ok {
+		return cty.NilVal, false
+	}
+
+	val, diags := tr.TraverseAbs(ctx)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() {
+		return cty.NilVal, false
+	}
+
+	return val, true
+}
+
+// evalExpression returns the value of the expr on the module c
+// if all the variables and locals it uses have a known value
+func evalExpression(c *configs.Config, expr hcl.Expression, vars map[string]cty.Value, visited map[string]struct{}) (cty.Value, bool) {
+	ctx, ok := evalContext(c, expr.Variables(), vars, visited)
+	if !ok {
+		return cty.NilVal, false
+	}
+
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() {
+		return cty.NilVal, false
+	}
+
+	return val, true
+}
+
+// evalContext returns the hcl.EvalContext with the values of all the
+// variables and locals used on the trs, if any of them has no known value
+// or it's not a variable or a local (like a resource) it returns false.
+// The visited holds the already evaluated ones so a local referencing
+// itself does not end on an infinite loop
+func evalContext(c *configs.Config, trs []hcl.Traversal, vars map[string]cty.Value, visited map[string]struct{}) (*hcl.EvalContext, bool) {
+	// values holds var|local -> Name -> Value
+	values := map[string]map[string]cty.Value{
+		"var":   make(map[string]cty.Value),
+		"local": make(map[string]cty.Value),
+	}
+
+	for _, tr := range trs {
+		names := traversalAttrNames(tr)
+		if len(names) < 2 {
+			return nil, false
+		}
+
+		key := fmt.Sprintf("%s:%s.%s", c.Path.String(), names[0], names[1])
+		if _, ok := visited[key]; ok {
+			return nil, false
+		}
+		visited[key] = struct{}{}
+
+		var (
+			val cty.Value
+			ok  bool
+		)
+		switch names[0] {
+		case "var":
+			val, ok = variableValue(c, names[1], vars, visited)
+		case "local":
+			if l, lok := c.Module.Locals[names[1]]; lok {
+				val, ok = evalExpression(c, l.Expr, vars, visited)
+			}
+		}
+		delete(visited, key)
+
+		if !ok {
+			return nil, false
+		}
+		values[names[0]][names[1]] = val
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: make(map[string]cty.Value),
+	}
+	for k, v := range values {
+		ctx.Variables[k] = cty.ObjectVal(v)
+	}
+
+	return ctx, true
+}
+
+// variableValue returns the value of the variable with the name on
+// the module c, for the root one it's from the vars and for the called
+// ones the argument of the call, if none it's the default of it
+func variableValue(c *configs.Config, name string, vars map[string]cty.Value, visited map[string]struct{}) (cty.Value, bool) {
+	if c.Parent == nil {
+		if val, ok := vars[name]; ok {
+			return val, true
+		}
+	} else if call, ok := c.Parent.Module.ModuleCalls[c.Path[len(c.Path)-1]]; ok {
+		attrs, _ := call.Config.JustAttributes()
+		if attr, ok := attrs[name]; ok {
+			return evalExpression(c.Parent, attr.Expr, vars, visited)
+		}
+	}
+
+	v, ok := c.Module.Variables[name]
+	if !ok || v.Default == cty.NilVal || v.Default.IsNull() {
+		return cty.NilVal, false
+	}
+
+	return v.Default, true
+}
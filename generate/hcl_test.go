@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/cycloidio/inframap/generate"
 	"github.com/cycloidio/inframap/graph"
@@ -71,6 +72,213 @@ func TestFromHCL_AWS(t *testing.T) {
 
 		assertEqualGraph(t, eg, g, cfg)
 	})
+	t.Run("SuccessSGJSON", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, cfg, err := generate.FromHCL(fs, "./testdata/aws_hcl_sg.tf.json", generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "im_out.tcp/443->443",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "some name",
+				},
+				&graph.Node{
+					Canonical: "aws_launch_template.front",
+				},
+				&graph.Node{
+					Canonical: "aws_db_instance.application",
+				},
+				&graph.Node{
+					Canonical: "aws_elasticache_cluster.redis",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "aws_lb.front",
+					Target:     "aws_launch_template.front",
+					Canonicals: []string{"aws_security_group.lb-front", "aws_security_group.front"},
+				},
+				&graph.Edge{
+					Source:     "aws_launch_template.front",
+					Target:     "aws_db_instance.application",
+					Canonicals: []string{"aws_security_group.front", "aws_security_group.rds"},
+				},
+				&graph.Edge{
+					Source:     "aws_launch_template.front",
+					Target:     "aws_elasticache_cluster.redis",
+					Canonicals: []string{"aws_security_group.redis", "aws_security_group.front"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessGroups", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, cfg, err := generate.FromHCL(fs, "./testdata/aws_hcl_groups.tf", generate.Options{Clean: true, Connections: true, Groups: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "front",
+					Group:     "aws_subnet.public",
+				},
+				&graph.Node{
+					Canonical: "aws_instance.web",
+					Group:     "aws_subnet.private",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "aws_lb.front",
+					Target:     "aws_instance.web",
+					Canonicals: []string{"aws_security_group.front", "aws_security_group.web"},
+				},
+			},
+			Groups: []*graph.Group{
+				&graph.Group{
+					Canonical: "aws_vpc.main",
+				},
+				&graph.Group{
+					Canonical: "aws_subnet.public",
+					Parent:    "aws_vpc.main",
+				},
+				&graph.Group{
+					Canonical: "aws_subnet.private",
+					Parent:    "aws_vpc.main",
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessLocals", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, cfg, err := generate.FromHCL(fs, "./testdata/aws_hcl_locals.tf", generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "some name",
+				},
+				&graph.Node{
+					Canonical: "aws_launch_template.front",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "aws_lb.front",
+					Target:     "aws_launch_template.front",
+					Canonicals: []string{"aws_security_group.lb-front", "aws_security_group.front"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessVariables", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, cfg, err := generate.FromHCL(fs, "./testdata/aws_hcl_locals.tf", generate.Options{Clean: true, Connections: true, ExternalNodes: true, Variables: map[string]cty.Value{"http_port": cty.NumberIntVal(8080)}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "im_out.tcp/8080->8080",
+				},
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "some name",
+				},
+				&graph.Node{
+					Canonical: "aws_launch_template.front",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/8080->8080",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "aws_lb.front",
+					Target:     "aws_launch_template.front",
+					Canonicals: []string{"aws_security_group.lb-front", "aws_security_group.front"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessExclude", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		f, err := generate.ParseFilter("aws_security_group.front")
+		require.NoError(t, err)
+
+		g, _, err := generate.FromHCL(fs, "./testdata/aws_hcl_sg.tf", generate.Options{Raw: true, Clean: true, Exclude: []generate.Filter{f}})
+		require.NoError(t, err)
+
+		// The aws_security_group.front referenced the lb-front
+		// so the ones referencing it reference the lb-front
+		nodes, err := g.Neighbours("aws_security_group.rds", graph.DirectionOut)
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		require.Equal(t, "aws_security_group.lb-front", nodes[0].Canonical)
+	})
+	t.Run("SuccessExcludeEdges", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		// The aws_security_group are the Edges of the
+		// Provider so they are never excluded
+		f, err := generate.ParseFilter("type:aws_security_group")
+		require.NoError(t, err)
+
+		g, _, err := generate.FromHCL(fs, "./testdata/aws_hcl_sg.tf", generate.Options{Clean: true, Connections: true, ExternalNodes: true, Exclude: []generate.Filter{f}})
+		require.NoError(t, err)
+
+		nodes, err := g.Neighbours("aws_lb.front", graph.DirectionOut)
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		require.Equal(t, "aws_launch_template.front", nodes[0].Canonical)
+	})
 }
 
 func TestFromHCL_FlexibleEngine(t *testing.T) {
@@ -204,6 +412,50 @@ func TestFromHCL_Module(t *testing.T) {
 			},
 		}
 
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessModuleCalls", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, cfg, err := generate.FromHCL(fs, "./testdata/tf-module-calls/", generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "some name",
+				},
+				&graph.Node{
+					Canonical: "module.front.aws_launch_template.front",
+				},
+				&graph.Node{
+					Canonical: "module.rds.aws_db_instance.application",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "aws_lb.front",
+					Target:     "module.front.aws_launch_template.front",
+					Canonicals: []string{"aws_security_group.lb-front", "module.front.aws_security_group.front"},
+				},
+				&graph.Edge{
+					Source:     "module.front.aws_launch_template.front",
+					Target:     "module.rds.aws_db_instance.application",
+					Canonicals: []string{"module.front.aws_security_group.front", "module.rds.aws_security_group.rds"},
+				},
+			},
+		}
+
 		assertEqualGraph(t, eg, g, cfg)
 	})
 }
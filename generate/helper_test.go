@@ -12,11 +12,35 @@ import (
 )
 
 // assertEqualGraph will compare the expected graph to the actual one, the way it'll do that is by ignoring the IDs
-// and only using the canonicals of the Node, same with the Edges
+// and only using the canonicals of the Node, same with the Edges and Groups. The Node.Group and Group.Parent
+// of the expected graph have to be the canonical of the Group
 func assertEqualGraph(t *testing.T, expected, actual *graph.Graph, actualCfg map[string]interface{}) {
 
 	assert.Len(t, actual.Nodes, len(expected.Nodes), "Nodes")
 	assert.Len(t, actual.Edges, len(expected.Edges), "Edges")
+	assert.Len(t, actual.Groups, len(expected.Groups), "Groups")
+
+	// groupCans holds canonical -> graph.Group
+	groupCans := make(map[string]*graph.Group)
+	for _, gr := range actual.Groups {
+		groupCans[gr.Canonical] = gr
+	}
+
+	for _, eg := range expected.Groups {
+		if ag, ok := groupCans[eg.Canonical]; ok {
+			if eg.Parent != "" {
+				if pg, ok := groupCans[eg.Parent]; ok {
+					eg.Parent = pg.ID
+				}
+			}
+			eg.ID = ag.ID
+			eg.TFID = ag.TFID
+			eg.Resource = ag.Resource
+			assert.Equal(t, eg, ag)
+		} else {
+			assert.Failf(t, "Fail", "The Group with Canonical %q is missing", eg.Canonical)
+		}
+	}
 
 	// nodeCans holds canonical -> graph.Node
 	nodeCans := make(map[string]*graph.Node)
@@ -51,6 +75,9 @@ func assertEqualGraph(t *testing.T, expected, actual *graph.Graph, actualCfg map
 
 	for _, en := range expected.Nodes {
 		if an, ok := nodeCans[en.Canonical]; ok {
+			if gr, ok := groupCans[en.Group]; ok {
+				en.Group = gr.ID
+			}
 			en.ID = an.ID
 			en.TFID = an.TFID
 			en.Resource = an.Resource
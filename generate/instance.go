@@ -0,0 +1,222 @@
+package generate
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/hashicorp/terraform/addrs"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+)
+
+// selectInstanceKeys returns the keys, of the instances of one resource,
+// that have to be used depending on the mode. They are sorted with the
+// ones of 'count' by the number, so '[2]' is before '[10]', and the ones
+// of 'for_each' by the key. With InstanceFirst only the '[0]' is used
+// and the resources with 'for_each' are ignored
+func selectInstanceKeys(keys []addrs.InstanceKey, mode InstanceMode) []addrs.InstanceKey {
+	res := make([]addrs.InstanceKey, 0, len(keys))
+	for _, k := range keys {
+		if mode == InstanceFirst && k != addrs.NoKey && k != addrs.IntKey(0) {
+			continue
+		}
+		res = append(res, k)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		// The nil key is the one of the resources without
+		// 'count' or 'for_each' so it'll be the only one
+		if res[i] == addrs.NoKey || res[j] == addrs.NoKey {
+			return res[i] == addrs.NoKey
+		}
+		ki, iok := res[i].(addrs.IntKey)
+		kj, jok := res[j].(addrs.IntKey)
+		if iok && jok {
+			return ki < kj
+		}
+		return res[i].String() < res[j].String()
+	})
+
+	if mode == InstanceCollapse && len(res) > 1 {
+		res = res[:1]
+	}
+
+	return res
+}
+
+// nodeInstance is one of the instances, from 'count' or
+// 'for_each', of a resource loaded with InstanceAll
+type nodeInstance struct {
+	Canonical string
+	Name      string
+	TFID      string
+	Action    graph.Action
+	Config    map[string]interface{}
+}
+
+// expandInstances replaces each Node of the lg that has instances with one
+// Node for each of them. With InstanceAll the resources are loaded as one
+// Node, as with InstanceCollapse, so the logic of the Providers is applied
+// the same way, and then each instance gets the Edges of the resource. If
+// the config of some instances has the TFID of the instances on the other
+// side of the Edge, like an 'aws_eip' of each 'aws_instance', only those are
+// connected, if not all the instances of both sides are
+func expandInstances(lg *loadedGraph, opt Options) error {
+	g := lg.g
+
+	// expanded holds the graph.Node.ID of the
+	// resource -> Nodes of the instances and the
+	// IDs are them on the order of the g.Nodes
+	expanded := make(map[string][]*graph.Node)
+	IDs := make([]string, 0)
+	for _, n := range g.Nodes {
+		ins, ok := lg.instances[n.ID]
+		if !ok {
+			continue
+		}
+
+		nodes := make([]*graph.Node, 0, len(ins))
+		for _, in := range ins {
+			inn := &graph.Node{
+				ID:        uuid.NewV4().String(),
+				Canonical: in.Canonical,
+				Name:      in.Name,
+				TFID:      in.TFID,
+				Resource:  n.Resource,
+				Action:    in.Action,
+				Weight:    n.Weight,
+				Group:     n.Group,
+			}
+
+			lg.cfg[inn.ID] = in.Config
+			lg.nodeCanIDs[inn.Canonical] = append(lg.nodeCanIDs[inn.Canonical], inn.ID)
+			lg.nodeStates[inn.ID] = lg.nodeStates[n.ID]
+
+			nodes = append(nodes, inn)
+		}
+		expanded[n.ID] = nodes
+		IDs = append(IDs, n.ID)
+	}
+
+	if len(expanded) == 0 {
+		return nil
+	}
+
+	// The Edges are copied before removing the
+	// Nodes as it also removes their Edges
+	edges := make([]graph.Edge, 0)
+	for _, e := range g.Edges {
+		_, sok := expanded[e.Source]
+		_, tok := expanded[e.Target]
+		if sok || tok {
+			edges = append(edges, *e)
+		}
+	}
+
+	// instanceNodes returns the Nodes of the instances
+	// of the Node with the ID or the Node itself
+	instanceNodes := func(ID string) ([]*graph.Node, error) {
+		if nodes, ok := expanded[ID]; ok {
+			return nodes, nil
+		}
+		n, err := g.GetNodeByID(ID)
+		if err != nil {
+			return nil, err
+		}
+		return []*graph.Node{n}, nil
+	}
+
+	for _, ID := range IDs {
+		err := g.RemoveNodeByID(ID)
+		if err != nil {
+			return err
+		}
+
+		for _, n := range expanded[ID] {
+			// Each instance could be on a different Group,
+			// like the instances spread on multiple subnets
+			if opt.Groups {
+				err = assignNodeGroup(g, n, lg.cfg[n.ID], opt)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = g.AddNode(n)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, e := range edges {
+		srcs, err := instanceNodes(e.Source)
+		if err != nil {
+			return err
+		}
+
+		tgts, err := instanceNodes(e.Target)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range instancePairs(lg, srcs, tgts) {
+			err = g.AddEdge(&graph.Edge{
+				ID:         uuid.NewV4().String(),
+				Source:     p[0].ID,
+				Target:     p[1].ID,
+				Canonicals: append([]string(nil), e.Canonicals...),
+				Action:     e.Action,
+			})
+			if err != nil {
+				// If the edge already exists we can ignore it
+				if errors.Is(err, errcode.ErrGraphAlreadyExistsEdge) {
+					continue
+				}
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// instancePairs returns the pairs of Nodes, from the srcs and the tgts,
+// that have to be connected. If the config of any of them has the TFID
+// of one of the other side those are the only ones, if not all of them
+func instancePairs(lg *loadedGraph, srcs, tgts []*graph.Node) [][2]*graph.Node {
+	all := make([][2]*graph.Node, 0, len(srcs)*len(tgts))
+	refs := make([][2]*graph.Node, 0)
+	for _, s := range srcs {
+		for _, t := range tgts {
+			p := [2]*graph.Node{s, t}
+			all = append(all, p)
+			if hasTFID(lg.cfg[s.ID], t) || hasTFID(lg.cfg[t.ID], s) {
+				refs = append(refs, p)
+			}
+		}
+	}
+
+	// If there is only one on one side it's
+	// connected to all the ones on the other
+	if len(refs) == 0 || len(srcs) == 1 || len(tgts) == 1 {
+		return all
+	}
+
+	return refs
+}
+
+// hasTFID checks if any of the values of the cfg is the TFID of the n
+func hasTFID(cfg map[string]interface{}, n *graph.Node) bool {
+	if n.TFID == "" {
+		return false
+	}
+	for _, v := range configValues(cfg) {
+		if v == n.TFID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package generate
+
+// InstanceMode defines how the instances of a resource,
+// created with 'count' or 'for_each', are represented
+type InstanceMode int
+
+//go:generate ./../bin/enumer -type=InstanceMode -transform=lower -trimprefix=Instance -output=instance_mode_string.go
+
+// List of all InstanceModes
+const (
+	// InstanceFirst only uses the first instance ('[0]')
+	// of each resource, the ones from 'for_each' are ignored
+	InstanceFirst InstanceMode = iota
+
+	// InstanceAll creates one Node for each instance
+	// with the key on the Canonical, like 'aws_instance.web["a"]'
+	InstanceAll
+
+	// InstanceCollapse creates one Node for each resource
+	// with the number of instances on graph.Node.Instances
+	InstanceCollapse
+)
@@ -1,5 +1,11 @@
 package generate
 
+import (
+	"encoding/json"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
 // Options are the possible options
 // that can be used to generate a Graph
 type Options struct {
@@ -19,4 +25,38 @@ type Options struct {
 	// Nodes detected to make the graph better,
 	// like the 'im_out'
 	ExternalNodes bool
+
+	// Groups will add the resources that contain
+	// Nodes, like VPCs or Subnets, as graph.Group
+	// and set on each Node the Group it belongs to
+	Groups bool
+
+	// Instances defines how the resources with multiple
+	// instances are represented. It's only used when
+	// generating from a TFState, as on the HCL the
+	// instances are not known
+	Instances InstanceMode
+
+	// Variables holds the values of the variables of
+	// the root module, like the ones from a '.tfvars'
+	// file. It's only used when generating from HCL,
+	// the variables not defined use the default value
+	Variables map[string]cty.Value
+
+	// RemoteStates holds the TFStates referenced by the
+	// 'terraform_remote_state' data sources, the key is
+	// the name of the data source or any value of its
+	// backend config, like the S3 'key'. It's only
+	// used when generating from a TFState
+	RemoteStates map[string]json.RawMessage
+
+	// Include, if set, only loads the resources that match
+	// any of the Filters and Exclude ignores the ones that
+	// match any of them. They are applied when loading so
+	// the Edges through the ignored resources are kept
+	// and the Providers logic is applied without them. The
+	// Edges of the Providers, like the security groups, are
+	// always loaded so the Nodes are still connected
+	Include []Filter
+	Exclude []Filter
 }
@@ -0,0 +1,681 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform/addrs"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/provider"
+)
+
+// jsonPlan is the representation of the plan
+// from 'terraform show -json plan', only with
+// the attributes used
+type jsonPlan struct {
+	FormatVersion   string               `json:"format_version"`
+	PlannedValues   *jsonValues          `json:"planned_values"`
+	ResourceChanges []jsonResourceChange `json:"resource_changes"`
+	Configuration   struct {
+		RootModule jsonConfigModule `json:"root_module"`
+	} `json:"configuration"`
+	PriorState *struct {
+		Values jsonValues `json:"values"`
+	} `json:"prior_state"`
+}
+
+// jsonValues holds the values of all the
+// resources, starting by the root module
+type jsonValues struct {
+	RootModule jsonModule `json:"root_module"`
+}
+
+// jsonModule is a module with the values of the resources
+type jsonModule struct {
+	Address      string         `json:"address"`
+	Resources    []jsonResource `json:"resources"`
+	ChildModules []jsonModule   `json:"child_modules"`
+}
+
+// jsonResource is a resource with its values,
+// the Address is the full one with the module
+type jsonResource struct {
+	Address   string                 `json:"address"`
+	Mode      string                 `json:"mode"`
+	Type      string                 `json:"type"`
+	Values    map[string]interface{} `json:"values"`
+	DependsOn []string               `json:"depends_on"`
+}
+
+// jsonResourceChange is the change planned for a resource
+type jsonResourceChange struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Change  struct {
+		Actions      []string               `json:"actions"`
+		AfterUnknown map[string]interface{} `json:"after_unknown"`
+	} `json:"change"`
+}
+
+// jsonConfigModule is the configuration of a module,
+// the Expressions are the ones on the HCL
+type jsonConfigModule struct {
+	Resources   []jsonConfigResource      `json:"resources"`
+	ModuleCalls map[string]jsonModuleCall `json:"module_calls"`
+	Outputs     map[string]struct {
+		Expression map[string]interface{} `json:"expression"`
+	} `json:"outputs"`
+}
+
+// jsonConfigResource is the configuration of a resource,
+// the Address is relative to the module
+type jsonConfigResource struct {
+	Address     string                 `json:"address"`
+	Expressions map[string]interface{} `json:"expressions"`
+	DependsOn   []string               `json:"depends_on"`
+}
+
+// jsonModuleCall is the call to a module with the
+// Expressions of the arguments
+type jsonModuleCall struct {
+	Expressions map[string]interface{} `json:"expressions"`
+	Module      jsonConfigModule       `json:"module"`
+}
+
+// planModule is a module of the configuration of the plan with the
+// path to it, so the references can be resolved through the calls
+type planModule struct {
+	Path   []string
+	Module *jsonConfigModule
+	Parent *planModule
+}
+
+// FromPlan generates a graph.Graph from the JSON of a plan, the output
+// of 'terraform show -json plan', applying the opt. The Nodes are the
+// planned resources and the destroyed ones, with the graph.Action
+// planned for each
+func FromPlan(plan json.RawMessage, opt Options) (*graph.Graph, map[string]interface{}, error) {
+	var p jsonPlan
+	err := json.Unmarshal(plan, &p)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while reading Plan: %w", err)
+	}
+
+	if p.FormatVersion == "" || p.PlannedValues == nil {
+		return nil, nil, errcode.ErrInvalidPlan
+	}
+
+	// changes holds the resource address -> change
+	changes := make(map[string]jsonResourceChange)
+	for _, rc := range p.ResourceChanges {
+		changes[rc.Address] = rc
+	}
+
+	resources := moduleResources(p.PlannedValues.RootModule)
+
+	// The destroyed resources are not on the planned values
+	// so they are taken from the state previous to the plan
+	if p.PriorState != nil {
+		for _, r := range moduleResources(p.PriorState.Values.RootModule) {
+			if planAction(changes[r.Address].Change.Actions) == graph.ActionDelete {
+				resources = append(resources, r)
+			}
+		}
+	}
+
+	if !opt.Raw {
+		opt, err = checkJSONProviders(resources, opt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	lg := newLoadedGraph()
+	err = loadJSONResources(lg, "", resources, changes, &p.Configuration.RootModule, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return processGraph(lg, opt)
+}
+
+// loadJSONResources loads the resources of the JSON representation of the
+// values, from the plan or 'terraform show -json', to the lg namespaced with
+// the name. The changes and config are the ones from the plan and are used
+// to set the graph.Action and the references of each resource
+func loadJSONResources(lg *loadedGraph, name string, resources []jsonResource, changes map[string]jsonResourceChange, config *jsonConfigModule, opt Options) error {
+	// Only the instances used following the opt.Instances
+	// are loaded, the same ones as from a statefile
+	resources, counts := jsonInstances(resources, opt.Instances)
+
+	// rnodes holds the Canonical -> Node of the
+	// resources with all the instances on InstanceAll
+	rnodes := make(map[string]*graph.Node)
+
+	for _, r := range resources {
+		// If it's not a Resource we ignore it
+		if r.Mode != "managed" {
+			continue
+		}
+
+		// can is the Canonical of the resource and ican
+		// the one of the instance, which only has the key
+		// if all the instances are displayed
+		can := prefixWithState(name, provider.TrimInstanceKey(r.Address))
+		ican := can
+		if opt.Instances == InstanceAll {
+			ican = prefixWithState(name, r.Address)
+		}
+
+		pv, rs, err := getProviderAndResource(can, opt)
+		if err != nil {
+			if errors.Is(err, errcode.ErrProviderNotFound) {
+				continue
+			}
+			return err
+		}
+
+		isGroup := opt.Groups && pv.IsGroup(rs)
+
+		// If it's not a Node, Edge or Group we ignore it
+		if !pv.IsNode(rs) && !pv.IsEdge(rs) && !isGroup {
+			continue
+		}
+
+		res, err := pv.Resource(rs)
+		if err != nil {
+			return err
+		}
+
+		rc := changes[r.Address]
+		aux := r.Values
+		if aux == nil {
+			aux = make(map[string]interface{})
+		}
+
+		deps := append([]string{}, r.DependsOn...)
+		if pm, cr := planConfigResource(config, provider.TrimInstanceKey(r.Address)); cr != nil {
+			deps = planConfigDependencies(pm, cr)
+
+			// The references are not known until applied so they
+			// are set from the configuration as they are on the HCL
+			for k, v := range planExpressionsConfig(pm, cr.Expressions, aux, rc.Change.AfterUnknown) {
+				aux[k] = v
+			}
+		}
+
+		// The dependencies are namespaced as
+		// the Canonicals if it has a name
+		for i, d := range deps {
+			deps[i] = prefixWithState(name, d)
+		}
+
+		if opt.filtered(name, jsonModuleAddress(r.Address), provider.TrimInstanceKey(r.Address), rs, pv) {
+			// Only the dependencies are kept so the
+			// resources can be connected through it
+			if !isGroup {
+				lg.excluded[can] = append(lg.excluded[can], deps...)
+			}
+			continue
+		}
+
+		rname := extractResourceName(aux)
+
+		var tfid string
+		if id, ok := aux["id"].(string); ok {
+			tfid = id
+		}
+
+		if isGroup {
+			gr := &graph.Group{
+				ID:        uuid.NewV4().String(),
+				Canonical: ican,
+				Name:      rname,
+				TFID:      tfid,
+				Resource:  *res,
+			}
+
+			err = lg.g.AddGroup(gr)
+			if err != nil {
+				return err
+			}
+
+			lg.groupCfg[gr.ID] = aux
+			continue
+		}
+
+		ins := nodeInstance{
+			Canonical: ican,
+			Name:      rname,
+			TFID:      tfid,
+			Action:    planAction(rc.Change.Actions),
+			Config:    aux,
+		}
+		if rn, ok := rnodes[can]; ok {
+			lg.instances[rn.ID] = append(lg.instances[rn.ID], ins)
+			continue
+		}
+
+		// The resource is one Node, from the first
+		// instance, and with InstanceAll it's expanded
+		// to the instances at the end of processGraph
+		n := &graph.Node{
+			ID:        uuid.NewV4().String(),
+			Canonical: can,
+			Name:      rname,
+			TFID:      tfid,
+			Resource:  *res,
+			Action:    ins.Action,
+		}
+
+		if opt.Instances == InstanceCollapse {
+			n.Instances = counts[provider.TrimInstanceKey(r.Address)]
+		}
+
+		err = lg.g.AddNode(n)
+		if err != nil {
+			return err
+		}
+
+		if ican != can {
+			rnodes[can] = n
+			lg.instances[n.ID] = []nodeInstance{ins}
+		}
+
+		lg.nodeCanIDs[can] = append(lg.nodeCanIDs[can], n.ID)
+		lg.nodeIDEdges[n.ID] = deps
+		lg.cfg[n.ID] = aux
+		lg.nodeStates[n.ID] = name
+	}
+
+	return nil
+}
+
+// jsonInstances returns the managed resources with only the instances
+// used following the mode, from selectInstanceKeys, on the order of the
+// first instance of each resource. It also returns the number of instances
+// of each resource by the Address without the instance key
+func jsonInstances(resources []jsonResource, mode InstanceMode) ([]jsonResource, map[string]int) {
+	// names holds the Addresses without the instance
+	// key on the order found and instances the instance
+	// key -> jsonResource of each one of them
+	names := make([]string, 0)
+	instances := make(map[string]map[addrs.InstanceKey]jsonResource)
+	for _, r := range resources {
+		if r.Mode != "managed" {
+			continue
+		}
+
+		a := provider.TrimInstanceKey(r.Address)
+		if _, ok := instances[a]; !ok {
+			names = append(names, a)
+			instances[a] = make(map[addrs.InstanceKey]jsonResource)
+		}
+
+		k := jsonInstanceKey(r.Address)
+		if _, ok := instances[a][k]; !ok {
+			instances[a][k] = r
+		}
+	}
+
+	res := make([]jsonResource, 0, len(resources))
+	counts := make(map[string]int, len(names))
+	for _, a := range names {
+		keys := make([]addrs.InstanceKey, 0, len(instances[a]))
+		for k := range instances[a] {
+			keys = append(keys, k)
+		}
+
+		for _, k := range selectInstanceKeys(keys, mode) {
+			res = append(res, instances[a][k])
+		}
+		counts[a] = len(keys)
+	}
+
+	return res, counts
+}
+
+// jsonInstanceKey returns the instance key of the address, like
+// 'aws_instance.web[0]', or nil if it has none or it's invalid
+func jsonInstanceKey(address string) addrs.InstanceKey {
+	ra, diags := addrs.ParseAbsResourceInstanceStr(address)
+	if diags.HasErrors() {
+		return addrs.NoKey
+	}
+	return ra.Resource.Key
+}
+
+// jsonModuleAddress returns the path of the module of the address,
+// like 'module.front["a.b"]' of 'module.front["a.b"].aws_lb.front',
+// or empty if it's on the root module or it's invalid
+func jsonModuleAddress(address string) string {
+	ra, diags := addrs.ParseAbsResourceInstanceStr(address)
+	if diags.HasErrors() {
+		return ""
+	}
+	return ra.Module.String()
+}
+
+// IsPlan checks if the b is the JSON of a plan,
+// the output of 'terraform show -json plan'
+func IsPlan(b []byte) bool {
+	var aux map[string]interface{}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return false
+	}
+
+	_, fok := aux["format_version"]
+	_, pok := aux["planned_values"]
+
+	return fok && pok
+}
+
+// moduleResources returns all the resources of
+// the m and of all the child modules of it
+func moduleResources(m jsonModule) []jsonResource {
+	res := make([]jsonResource, 0, len(m.Resources))
+	res = append(res, m.Resources...)
+	for _, cm := range m.ChildModules {
+		res = append(res, moduleResources(cm)...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Address < res[j].Address })
+
+	return res
+}
+
+// planAction converts the actions of a
+// resource change to the graph.Action
+func planAction(actions []string) graph.Action {
+	switch strings.Join(actions, ",") {
+	case "create":
+		return graph.ActionCreate
+	case "update":
+		return graph.ActionUpdate
+	case "delete":
+		return graph.ActionDelete
+	case "delete,create", "create,delete":
+		return graph.ActionReplace
+	}
+	return graph.ActionNone
+}
+
+// planConfigResource returns the configuration of the resource with the
+// Canonical can, which could be on a module, and the module it's on
+func planConfigResource(root *jsonConfigModule, can string) (*planModule, *jsonConfigResource) {
+	if root == nil {
+		return nil, nil
+	}
+
+	keys := strings.Split(can, ".")
+
+	pm := &planModule{Module: root}
+	for len(keys) > 2 && keys[0] == "module" {
+		call, ok := pm.Module.ModuleCalls[keys[1]]
+		if !ok {
+			return nil, nil
+		}
+
+		pm = &planModule{
+			Path:   append(append([]string{}, pm.Path...), keys[1]),
+			Module: &call.Module,
+			Parent: pm,
+		}
+		keys = keys[2:]
+	}
+
+	addr := strings.Join(keys, ".")
+	for i, cr := range pm.Module.Resources {
+		if cr.Address == addr {
+			return pm, &pm.Module.Resources[i]
+		}
+	}
+
+	return nil, nil
+}
+
+// planConfigDependencies returns the Canonicals of all the resources
+// referenced on the expressions and the 'depends_on' of the cr
+func planConfigDependencies(pm *planModule, cr *jsonConfigResource) []string {
+	refs := append(expressionsReferences(cr.Expressions), cr.DependsOn...)
+
+	deps := make([]string, 0, len(refs))
+	seen := make(map[string]struct{})
+	for _, ref := range refs {
+		for _, r := range pm.resolveReference(ref, make(map[string]struct{})) {
+			if _, ok := seen[r.Canonical]; ok {
+				continue
+			}
+			seen[r.Canonical] = struct{}{}
+			deps = append(deps, r.Canonical)
+		}
+	}
+
+	return deps
+}
+
+// expressionsReferences returns all the references on the exprs,
+// also the ones of the blocks, which are lists of expressions
+func expressionsReferences(exprs map[string]interface{}) []string {
+	refs := make([]string, 0)
+	for _, v := range exprs {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			if rs, ok := vv["references"].([]interface{}); ok {
+				for _, r := range rs {
+					if s, ok := r.(string); ok {
+						refs = append(refs, s)
+					}
+				}
+			}
+		case []interface{}:
+			for _, b := range vv {
+				if mb, ok := b.(map[string]interface{}); ok {
+					refs = append(refs, expressionsReferences(mb)...)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// planExpressionsConfig returns the config of the attributes of the exprs
+// that reference other resources, in the same format the getBodyJSON does
+// for the HCL '${aws_security_group.front.id}'. The values and unknown are
+// the planned values of the resource and the ones not known until applied,
+// used to know if the attributes are lists
+func planExpressionsConfig(pm *planModule, exprs map[string]interface{}, values, unknown map[string]interface{}) map[string]interface{} {
+	cfg := make(map[string]interface{})
+	for k, v := range exprs {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			rs, ok := vv["references"].([]interface{})
+			if !ok {
+				continue
+			}
+
+			refs := make([]interface{}, 0)
+			seen := make(map[string]struct{})
+			for _, r := range rs {
+				s, ok := r.(string)
+				if !ok {
+					continue
+				}
+				for _, ref := range pm.resolveReference(s, make(map[string]struct{})) {
+					if _, ok := seen[ref.Canonical]; ok || ref.Attribute == "" {
+						continue
+					}
+					seen[ref.Canonical] = struct{}{}
+					refs = append(refs, fmt.Sprintf("${%s}", ref))
+				}
+			}
+
+			if len(refs) == 0 {
+				continue
+			}
+
+			_, vlok := values[k].([]interface{})
+			_, ulok := unknown[k].([]interface{})
+			if vlok || ulok {
+				cfg[k] = refs
+			} else {
+				cfg[k] = refs[0]
+			}
+		case []interface{}:
+			// The blocks are only replaced if
+			// they have any reference on them
+			if len(expressionsReferences(map[string]interface{}{k: vv})) == 0 {
+				continue
+			}
+
+			vbs, _ := values[k].([]interface{})
+			ubs, _ := unknown[k].([]interface{})
+			blocks := make([]interface{}, 0, len(vv))
+			for i, b := range vv {
+				mb, ok := b.(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				bcfg := make(map[string]interface{})
+				for bk, bv := range mb {
+					if bexpr, ok := bv.(map[string]interface{}); ok {
+						if cv, ok := bexpr["constant_value"]; ok {
+							bcfg[bk] = cv
+						}
+					}
+				}
+
+				vb, _ := blockAt(vbs, i).(map[string]interface{})
+				ub, _ := blockAt(ubs, i).(map[string]interface{})
+				for bk, bv := range planExpressionsConfig(pm, mb, vb, ub) {
+					bcfg[bk] = bv
+				}
+				blocks = append(blocks, bcfg)
+			}
+			cfg[k] = blocks
+		}
+	}
+
+	return cfg
+}
+
+// blockAt returns the block on the position i of the bs
+// or the first one if there are less, as all of them
+// have the same attributes
+func blockAt(bs []interface{}, i int) interface{} {
+	if i < len(bs) {
+		return bs[i]
+	} else if len(bs) != 0 {
+		return bs[0]
+	}
+	return nil
+}
+
+// resolveReference returns the resources the ref points to from the
+// pm following the variables and the outputs of the modules, as
+// the resolveReference of the HCL does with the configs.Config
+func (pm *planModule) resolveReference(ref string, visited map[string]struct{}) []hclReference {
+	key := fmt.Sprintf("%s:%s", strings.Join(pm.Path, "."), ref)
+	if _, ok := visited[key]; ok {
+		return nil
+	}
+	visited[key] = struct{}{}
+
+	tr, diags := hclsyntax.ParseTraversalAbs([]byte(ref), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil
+	}
+
+	names := traversalAttrNames(tr)
+	if len(names) < 2 {
+		return nil
+	}
+
+	switch names[0] {
+	case "var":
+		if pm.Parent == nil {
+			return nil
+		}
+
+		call := pm.Parent.Module.ModuleCalls[pm.Path[len(pm.Path)-1]]
+		exprRefs := expressionsReferences(map[string]interface{}{names[1]: call.Expressions[names[1]]})
+
+		res := make([]hclReference, 0)
+		for _, r := range exprRefs {
+			res = append(res, pm.Parent.resolveReference(r, visited)...)
+		}
+		return res
+	case "module":
+		call, ok := pm.Module.ModuleCalls[names[1]]
+		if !ok || len(names) < 3 {
+			return nil
+		}
+
+		child := &planModule{
+			Path:   append(append([]string{}, pm.Path...), names[1]),
+			Module: &call.Module,
+			Parent: pm,
+		}
+		exprRefs := expressionsReferences(map[string]interface{}{names[2]: call.Module.Outputs[names[2]].Expression})
+
+		res := make([]hclReference, 0)
+		for _, r := range exprRefs {
+			res = append(res, child.resolveReference(r, visited)...)
+		}
+		return res
+	case "data", "local", "count", "each", "path", "self", "terraform":
+		return nil
+	}
+
+	mp := make([]string, 0, len(pm.Path)*2)
+	for _, p := range pm.Path {
+		mp = append(mp, "module", p)
+	}
+
+	r := hclReference{
+		Canonical: prefixWithModule(strings.Join(mp, "."), fmt.Sprintf("%s.%s", names[0], names[1])),
+	}
+	if len(names) > 2 {
+		r.Attribute = names[2]
+	}
+
+	return []hclReference{r}
+}
+
+// checkJSONProviders checks if we support any of the Providers from the resources, if not it'll set
+// the opt.Raw to true so it can be used with Raw instead of returning an empty Graph
+func checkJSONProviders(resources []jsonResource, opt Options) (Options, error) {
+	for _, r := range resources {
+		// If it's not a Resource we ignore it
+		if r.Mode != "managed" {
+			continue
+		}
+
+		_, _, err := getProviderAndResource(provider.TrimInstanceKey(r.Address), opt)
+		if err != nil {
+			if errors.Is(err, errcode.ErrProviderNotFound) {
+				continue
+			}
+			return opt, err
+		}
+
+		// If we find a resource that we support the Provider
+		// then we use it
+		return opt, nil
+	}
+
+	// If we reach here means the we do not support the providers
+	// of the resources, so Raw has to be used
+	opt.Raw = true
+
+	return opt, nil
+}
@@ -0,0 +1,97 @@
+package generate_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/generate"
+	"github.com/cycloidio/inframap/graph"
+)
+
+func TestFromPlan_AWS(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_plan.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromPlan(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					TFID:      "lb-1",
+					Name:      "front",
+					Action:    graph.ActionUpdate,
+				},
+				&graph.Node{
+					Canonical: "aws_launch_template.front",
+					Action:    graph.ActionCreate,
+				},
+				&graph.Node{
+					Canonical: "aws_db_instance.application",
+					Action:    graph.ActionReplace,
+				},
+				&graph.Node{
+					Canonical: "aws_elasticache_cluster.redis",
+					TFID:      "redis-1",
+					Name:      "redis-1",
+					Action:    graph.ActionDelete,
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "aws_lb.front",
+					Target:     "aws_launch_template.front",
+					Canonicals: []string{"aws_security_group.lb-front", "aws_security_group.front"},
+				},
+				&graph.Edge{
+					Source:     "aws_launch_template.front",
+					Target:     "aws_db_instance.application",
+					Canonicals: []string{"aws_security_group.front", "aws_security_group.rds"},
+				},
+				&graph.Edge{
+					Source:     "aws_lb.front",
+					Target:     "aws_elasticache_cluster.redis",
+					Canonicals: []string{"aws_security_group.lb-front", "aws_security_group.redis"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("ErrInvalidPlan", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_sg.json")
+		require.NoError(t, err)
+
+		_, _, err = generate.FromPlan(src, generate.Options{Clean: true, Connections: true})
+		assert.True(t, errors.Is(err, errcode.ErrInvalidPlan))
+	})
+}
+
+func TestIsPlan(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_plan.json")
+		require.NoError(t, err)
+		assert.True(t, generate.IsPlan(src))
+	})
+	t.Run("SuccessState", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_sg.json")
+		require.NoError(t, err)
+		assert.False(t, generate.IsPlan(src))
+	})
+}
@@ -0,0 +1,156 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cycloidio/flatmap"
+	"github.com/hashicorp/terraform/addrs"
+
+	"github.com/cycloidio/inframap/provider"
+)
+
+// remoteStateType is the type of the data source
+// used to read the outputs of another TFState
+const remoteStateType = "terraform_remote_state"
+
+// remoteState is a 'terraform_remote_state' data source,
+// the Name is the one of the data source and the Config
+// is the configuration of the backend it reads from
+type remoteState struct {
+	Name   string
+	Config interface{}
+}
+
+// remoteStates returns all the 'terraform_remote_state'
+// data sources of the s, on all the modules
+func (s *parsedState) remoteStates() []remoteState {
+	res := make([]remoteState, 0)
+
+	if s.File == nil {
+		for _, r := range s.Resources {
+			if r.Mode != "data" || r.Type != remoteStateType {
+				continue
+			}
+
+			// The Address is like 'data.terraform_remote_state.network'
+			// and it could be on a module or have an instance key
+			addr := provider.TrimInstanceKey(r.Address)
+			res = append(res, remoteState{
+				Name:   addr[strings.LastIndex(addr, ".")+1:],
+				Config: r.Values["config"],
+			})
+		}
+
+		return res
+	}
+
+	for _, m := range s.File.State.Modules {
+		for _, rv := range m.Resources {
+			if rv.Addr.Resource.Mode != addrs.DataResourceMode || rv.Addr.Resource.Type != remoteStateType {
+				continue
+			}
+
+			for _, iv := range rv.Instances {
+				if iv.Current == nil {
+					continue
+				}
+
+				var cfg interface{}
+				if iv.Current.AttrsJSON != nil {
+					// For TF +0.12
+					var aux map[string]interface{}
+					if err := json.Unmarshal(iv.Current.AttrsJSON, &aux); err != nil {
+						continue
+					}
+					cfg = aux["config"]
+
+					// The 'config' is a dynamic value so it's
+					// stored with the 'value' and the 'type'
+					if mcfg, ok := cfg.(map[string]interface{}); ok && mcfg["type"] != nil {
+						cfg = mcfg["value"]
+					}
+				} else {
+					// For TF 0.11
+					cfg = flatmap.Expand(iv.Current.AttrsFlat, "config")
+				}
+
+				res = append(res, remoteState{
+					Name:   rv.Addr.Resource.Name,
+					Config: cfg,
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+// loadRemoteStates parses the TFStates of the remotes referenced by
+// the 'terraform_remote_state' of the states, and the ones referenced by
+// those, and returns them with the states. Each one is named as the data
+// source that references it and it's only loaded once, the ones with a
+// name already used are expected to be already on the states
+func loadRemoteStates(states []*parsedState, remotes map[string]json.RawMessage) ([]*parsedState, error) {
+	if len(remotes) == 0 {
+		return states, nil
+	}
+
+	names := make(map[string]struct{})
+	for _, s := range states {
+		names[s.Name] = struct{}{}
+	}
+
+	// loaded holds the keys of the remotes already used
+	loaded := make(map[string]struct{})
+
+	// The states grow while iterating as the
+	// remote ones could also reference others
+	for i := 0; i < len(states); i++ {
+		for _, rs := range states[i].remoteStates() {
+			key, ok := remoteStateKey(rs, remotes)
+			if !ok {
+				continue
+			}
+			if _, ok := loaded[key]; ok {
+				continue
+			}
+			loaded[key] = struct{}{}
+
+			if _, ok := names[rs.Name]; ok {
+				continue
+			}
+			names[rs.Name] = struct{}{}
+
+			s, err := parseState(rs.Name, remotes[key])
+			if err != nil {
+				return nil, fmt.Errorf("remote state %q: %w", rs.Name, err)
+			}
+			states = append(states, s)
+		}
+	}
+
+	return states, nil
+}
+
+// remoteStateKey returns the key of the remotes that
+// matches the rs, by the Name of the data source or by
+// any of the values of the Config, like the S3 'key'
+// or the local 'path'
+func remoteStateKey(rs remoteState, remotes map[string]json.RawMessage) (string, bool) {
+	if _, ok := remotes[rs.Name]; ok {
+		return rs.Name, true
+	}
+
+	values := configValues(rs.Config)
+	sort.Strings(values)
+	for _, v := range values {
+		if _, ok := remotes[v]; ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,27 @@
+package generate
+
+import "encoding/json"
+
+// jsonShowState is the representation of the state
+// from 'terraform show -json', only with the
+// attributes used
+type jsonShowState struct {
+	FormatVersion string      `json:"format_version"`
+	Values        *jsonValues `json:"values"`
+}
+
+// isShowState checks if the b is the JSON of the
+// state from 'terraform show -json' instead of
+// the statefile, which has 'version' instead
+// of 'format_version'
+func isShowState(b []byte) bool {
+	var aux map[string]interface{}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return false
+	}
+
+	_, fok := aux["format_version"]
+	_, vok := aux["version"]
+
+	return fok && !vok
+}
@@ -0,0 +1,139 @@
+package generate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/configs"
+	"github.com/spf13/afero"
+)
+
+// StackType defines the type of the input of a Stack
+type StackType int
+
+//go:generate ./../bin/enumer -type=StackType -transform=lower -trimprefix=Stack -output=stack_type_string.go
+
+// List of all StackTypes
+const (
+	// StackHCL is a directory with a root module
+	StackHCL StackType = iota
+
+	// StackTFState is a TFState file
+	StackTFState
+)
+
+// Stack is one of the stacks found by FindStacks
+type Stack struct {
+	// Name is the path of the stack relative to the root
+	// used on FindStacks, like 'network/prod', for the
+	// TFStates it has no extension 'network/terraform'
+	Name string
+
+	// Path is the path to the directory of the
+	// root module or to the TFState file
+	Path string
+
+	Type StackType
+
+	// Err is the error found while reading the stack,
+	// like an invalid HCL, if it's not nil the Graph
+	// of the stack can not be generated
+	Err error
+}
+
+// FindStacks walks the root and returns all the root modules and the
+// TFStates ('.tfstate') under it, sorted by the Path. The directories
+// with HCL that are called as a module, with a local source, from
+// another directory are not root modules so they are not returned.
+// The hidden directories, like '.terraform', are ignored. If the HCL
+// of a directory is invalid the error is on the Err of its Stack
+// and the rest of the directories are still walked
+func FindStacks(fs afero.Fs, root string) ([]Stack, error) {
+	parser := configs.NewParser(fs)
+
+	stacks := make([]Stack, 0)
+
+	// modules holds the directories called as
+	// a module from any of the directories
+	modules := make(map[string]struct{})
+
+	err := afero.Walk(fs, root, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			if p != root && strings.HasPrefix(fi.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			if !parser.IsConfigDir(p) {
+				return nil
+			}
+
+			s := Stack{
+				Name: stackName(root, p),
+				Path: p,
+				Type: StackHCL,
+			}
+
+			mod, diags := parser.LoadConfigDir(p)
+			if diags.HasErrors() {
+				s.Err = errors.New(diags.Error())
+			}
+
+			// With errors the mod can still have
+			// the module calls that were valid
+			if mod != nil {
+				for _, mc := range mod.ModuleCalls {
+					if isLocalSourceAddr(mc.SourceAddr) {
+						modules[filepath.Join(p, mc.SourceAddr)] = struct{}{}
+					}
+				}
+			}
+
+			stacks = append(stacks, s)
+		} else if filepath.Ext(p) == ".tfstate" {
+			stacks = append(stacks, Stack{
+				Name: strings.TrimSuffix(stackName(root, p), ".tfstate"),
+				Path: p,
+				Type: StackTFState,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Stack, 0, len(stacks))
+	for _, s := range stacks {
+		if _, ok := modules[filepath.Clean(s.Path)]; ok && s.Type == StackHCL {
+			continue
+		}
+		res = append(res, s)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Path < res[j].Path })
+
+	return res, nil
+}
+
+// stackName returns the path p relative to the root using
+// always '/', if it's the root itself it's the base of it
+func stackName(root, p string) string {
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == "." {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return filepath.Base(p)
+		}
+		return filepath.Base(abs)
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,77 @@
+package generate_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/generate"
+)
+
+func TestFindStacks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		files := map[string]string{
+			"stacks/network/main.tf":                    `resource "aws_vpc" "main" {}`,
+			"stacks/network/terraform.tfstate":          `{}`,
+			"stacks/network/terraform.tfstate.backup":   `{}`,
+			"stacks/app/main.tf":                        `module "web" { source = "../modules/web" }`,
+			"stacks/app/.terraform/modules/web/main.tf": `resource "aws_instance" "web" {}`,
+			"stacks/modules/web/main.tf":                `resource "aws_instance" "web" {}`,
+			"stacks/states/app.tfstate":                 `{}`,
+			"stacks/README.md":                          `# Stacks`,
+		}
+		for p, c := range files {
+			require.NoError(t, afero.WriteFile(fs, p, []byte(c), 0644))
+		}
+
+		stacks, err := generate.FindStacks(fs, "stacks")
+		require.NoError(t, err)
+		assert.Equal(t, []generate.Stack{
+			generate.Stack{Name: "app", Path: "stacks/app", Type: generate.StackHCL},
+			generate.Stack{Name: "network", Path: "stacks/network", Type: generate.StackHCL},
+			generate.Stack{Name: "network/terraform", Path: "stacks/network/terraform.tfstate", Type: generate.StackTFState},
+			generate.Stack{Name: "states/app", Path: "stacks/states/app.tfstate", Type: generate.StackTFState},
+		}, stacks)
+	})
+	t.Run("SuccessInvalidHCL", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		files := map[string]string{
+			"stacks/network/main.tf":        `resource "aws_vpc" "main" {}`,
+			"stacks/invalid/main.tf":        `resource "aws_instance" {`,
+			"stacks/states/app.tfstate":     `{}`,
+			"stacks/zone/main.tf":           `resource "aws_route53_zone" "main" {}`,
+			"stacks/zone/terraform.tfstate": `{}`,
+		}
+		for p, c := range files {
+			require.NoError(t, afero.WriteFile(fs, p, []byte(c), 0644))
+		}
+
+		stacks, err := generate.FindStacks(fs, "stacks")
+		require.NoError(t, err)
+		require.Len(t, stacks, 5)
+
+		assert.Equal(t, "invalid", stacks[0].Name)
+		assert.Error(t, stacks[0].Err)
+
+		// The Err is checked before so the rest
+		// can be compared with the expected ones
+		stacks[0].Err = nil
+		assert.Equal(t, []generate.Stack{
+			generate.Stack{Name: "invalid", Path: "stacks/invalid", Type: generate.StackHCL},
+			generate.Stack{Name: "network", Path: "stacks/network", Type: generate.StackHCL},
+			generate.Stack{Name: "states/app", Path: "stacks/states/app.tfstate", Type: generate.StackTFState},
+			generate.Stack{Name: "zone", Path: "stacks/zone", Type: generate.StackHCL},
+			generate.Stack{Name: "zone/terraform", Path: "stacks/zone/terraform.tfstate", Type: generate.StackTFState},
+		}, stacks)
+	})
+	t.Run("SuccessRootModule", func(t *testing.T) {
+		stacks, err := generate.FindStacks(afero.NewOsFs(), "./testdata/tf-module-calls")
+		require.NoError(t, err)
+		assert.Equal(t, []generate.Stack{
+			generate.Stack{Name: "tf-module-calls", Path: "./testdata/tf-module-calls", Type: generate.StackHCL},
+		}, stacks)
+	})
+}
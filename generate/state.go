@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/states"
 	"github.com/hashicorp/terraform/states/statefile"
 	uuid "github.com/satori/go.uuid"
 
@@ -20,49 +22,202 @@ import (
 	"github.com/cycloidio/inframap/provider/factory"
 )
 
-// FromState generate a graph.Graph from the tfstate applying the opt
+// FromState generate a graph.Graph from the tfstate applying the opt,
+// the tfstate can also be the output of 'terraform show -json'
 func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]interface{}, error) {
+	return FromStates(map[string]json.RawMessage{"": tfstate}, opt)
+}
+
+// FromStates generates one graph.Graph from all the tfstates applying the opt.
+// The key is the name used to namespace the Canonicals of the resources of
+// each TFState, like 'network.aws_vpc.main', and the resources of different
+// TFStates are connected if the config of one has the TFID of the other.
+// The TFStates of the opt.RemoteStates referenced by a 'terraform_remote_state'
+// are also loaded, namespaced with the name of the data source
+func FromStates(tfstates map[string]json.RawMessage, opt Options) (*graph.Graph, map[string]interface{}, error) {
+	names := make([]string, 0, len(tfstates))
+	for n := range tfstates {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	states := make([]*parsedState, 0, len(names))
+	for _, n := range names {
+		s, err := parseState(n, tfstates[n])
+		if err != nil {
+			if n != "" {
+				return nil, nil, fmt.Errorf("state %q: %w", n, err)
+			}
+			return nil, nil, err
+		}
+		states = append(states, s)
+	}
+
+	states, err := loadRemoteStates(states, opt.RemoteStates)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The Raw is only used if none of the
+	// TFStates has a Provider we support
+	if !opt.Raw {
+		raw := true
+		for _, s := range states {
+			sopt, err := s.checkProviders(opt)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !sopt.Raw {
+				raw = false
+				break
+			}
+		}
+		opt.Raw = raw
+	}
+
+	lg := newLoadedGraph()
+	for _, s := range states {
+		if s.File != nil {
+			err = loadStatefile(lg, s, opt)
+		} else {
+			err = loadJSONResources(lg, s.Name, s.Resources, nil, nil, opt)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(states) > 1 {
+		err = linkStates(lg)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return processGraph(lg, opt)
+}
+
+// parsedState is a TFState already read, from the statefile,
+// which is on the File, or from 'terraform show -json', which
+// has the Resources
+type parsedState struct {
+	// Name is the name used to namespace
+	// the Canonicals of the resources
+	Name string
+
+	File *statefile.File
+
+	// HasDependsOn is set if the File uses
+	// the 'depends_on' instead of 'dependencies'
+	HasDependsOn bool
+
+	Resources []jsonResource
+}
+
+// parseState reads the tfstate with the name
+func parseState(name string, tfstate json.RawMessage) (*parsedState, error) {
+	s := &parsedState{
+		Name: name,
+	}
+
+	if isShowState(tfstate) {
+		var ss jsonShowState
+		err := json.Unmarshal(tfstate, &ss)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading TFState: %w", err)
+		}
+
+		// An empty state has no values
+		s.Resources = make([]jsonResource, 0)
+		if ss.Values != nil {
+			s.Resources = moduleResources(ss.Values.RootModule)
+		}
+
+		return s, nil
+	}
+
 	// Since TF 0.13 'depends_on' has been dropped, so we do a manual
 	// replace from '"depends_on"' to '"dependencies"'
-	hasDependsOn := bytes.Contains(tfstate, []byte("\"depends_on\""))
+	s.HasDependsOn = bytes.Contains(tfstate, []byte("\"depends_on\""))
 	tfstate = bytes.ReplaceAll(tfstate, []byte("\"depends_on\""), []byte("\"dependencies\""))
 	err := ValidateTFStateVersion(tfstate)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error while validating TFStateVersion: %w", err)
+		return nil, fmt.Errorf("error while validating TFStateVersion: %w", err)
 	}
 
 	buf := bytes.NewBuffer(tfstate)
 
-	file, err := statefile.Read(buf)
+	s.File, err = statefile.Read(buf)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error while reading TFState: %w", err)
+		return nil, fmt.Errorf("error while reading TFState: %w", err)
 	}
 
-	migrateVersions(tfstate, file)
+	migrateVersions(tfstate, s.File)
+
+	return s, nil
+}
 
-	g := graph.New()
+// checkProviders checks if we support any of the Providers of the s
+func (s *parsedState) checkProviders(opt Options) (Options, error) {
+	if s.File != nil {
+		return checkProviders(s.File, opt)
+	}
+	return checkJSONProviders(s.Resources, opt)
+}
+
+// loadedGraph holds the graph.Graph with the resources loaded
+// and all the information needed to apply the logic of
+// the Providers to it on processGraph
+type loadedGraph struct {
+	g *graph.Graph
 
 	// cfg holds the actual configuration of each element
 	// it's represented as: ID -> Attrs
-	cfg := make(map[string]map[string]interface{})
+	cfg map[string]map[string]interface{}
 
 	// nodeCanIDs holds as key the `aws_alb.front` (graph.Node.Canonical)
 	// and as value the UUID (graph.Node.ID) we give to it
-	nodeCanIDs := make(map[string][]string)
+	nodeCanIDs map[string][]string
 
 	// nodeIDEdges holds as key the UUID (graph.Node.ID) and as value
 	// all the edges it has, in this case it's the `depends_on` values
 	// that we find on the TFState
-	nodeIDEdges := make(map[string][]string)
+	nodeIDEdges map[string][]string
 
-	if !opt.Raw {
-		opt, err = checkProviders(file, opt)
-		if err != nil {
-			return nil, nil, err
-		}
+	// groupCfg holds the actual configuration of each Group
+	// it's represented as: graph.Group.ID -> Attrs
+	groupCfg map[string]map[string]interface{}
+
+	// nodeStates holds the graph.Node.ID -> Name
+	// of the TFState in which the resource is
+	nodeStates map[string]string
+
+	// excluded holds the Canonical -> dependencies of the
+	// resources ignored by the Options.Include and Exclude
+	excluded map[string][]string
+
+	// instances holds the graph.Node.ID -> instances of the
+	// resources loaded with InstanceAll, which are expanded
+	// to one Node each on expandInstances
+	instances map[string][]nodeInstance
+}
+
+func newLoadedGraph() *loadedGraph {
+	return &loadedGraph{
+		g:           graph.New(),
+		cfg:         make(map[string]map[string]interface{}),
+		nodeCanIDs:  make(map[string][]string),
+		nodeIDEdges: make(map[string][]string),
+		groupCfg:    make(map[string]map[string]interface{}),
+		nodeStates:  make(map[string]string),
+		excluded:    make(map[string][]string),
+		instances:   make(map[string][]nodeInstance),
 	}
+}
 
-	for _, m := range file.State.Modules {
+// loadStatefile loads all the resources of the statefile of s to the lg
+func loadStatefile(lg *loadedGraph, s *parsedState, opt Options) error {
+	for _, m := range s.File.State.Modules {
 		for rk, rv := range m.Resources {
 			// If it's not a Resource we ignore it
 			if rv.Addr.Resource.Mode != addrs.ManagedResourceMode {
@@ -74,20 +229,26 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 				if errors.Is(err, errcode.ErrProviderNotFound) {
 					continue
 				}
-				return nil, nil, err
+				return err
 			}
 
-			// If it's not a Node or Edge we ignore it
-			if !pv.IsNode(rs) && !pv.IsEdge(rs) {
+			isGroup := opt.Groups && pv.IsGroup(rs)
+
+			// If it's not a Node, Edge or Group we ignore it
+			if !pv.IsNode(rs) && !pv.IsEdge(rs) && !isGroup {
 				continue
 			}
 
+			excluded := opt.filtered(s.Name, m.Addr.String(), prefixWithModule(m.Addr.String(), rk), rs, pv)
+
+			// rn is the Node of the resource, which
+			// has all the instances with InstanceAll
+			var rn *graph.Node
+
 			// The Instances is the representation of the
 			// 'count' on the Instance, could also be a 'for_each'
-			for id, iv := range rv.Instances {
-				if id != nil && id.String() != "[0]" {
-					continue
-				}
+			for _, id := range instanceKeys(rv.Instances, opt.Instances) {
+				iv := rv.Instances[id]
 				deps := make([]string, 0)
 				if len(iv.Current.Dependencies) != 0 {
 					deps = append(deps, instanceCurrentDependenciesToString(iv.Current.Dependencies)...)
@@ -98,7 +259,7 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 				// This is only needed on the versions using `depends_on`
 				// for what we have found. The ones with `dependencies` have
 				// the right reference always.
-				if hasDependsOn {
+				if s.HasDependsOn {
 					for i, d := range deps {
 						if !strings.HasPrefix(d, "module.") {
 							deps[i] = prefixWithModule(m.Addr.String(), d)
@@ -106,12 +267,28 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 					}
 				}
 
+				// The dependencies are namespaced as
+				// the Canonicals if it has a Name
+				for i, d := range deps {
+					deps[i] = prefixWithState(s.Name, d)
+				}
+
+				if excluded {
+					// Only the dependencies are kept so the
+					// resources can be connected through it
+					if !isGroup {
+						can := prefixWithState(s.Name, prefixWithModule(m.Addr.String(), rk))
+						lg.excluded[can] = append(lg.excluded[can], deps...)
+					}
+					continue
+				}
+
 				aux := make(map[string]interface{})
 				if iv.Current.AttrsJSON != nil {
 					// For TF +0.12
 					err = json.Unmarshal(iv.Current.AttrsJSON, &aux)
 					if err != nil {
-						return nil, nil, fmt.Errorf("invalid fomrat JSON for resource %q with AttrsJSON %s: %w", string(iv.Current.AttrsJSON), rk, err)
+						return fmt.Errorf("invalid fomrat JSON for resource %q with AttrsJSON %s: %w", string(iv.Current.AttrsJSON), rk, err)
 					}
 				} else {
 					// For TF 0.11
@@ -131,58 +308,136 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 
 				res, err := pv.Resource(rs)
 				if err != nil {
-					return nil, nil, err
+					return err
 				}
 
 				tfid, ok := aux["id"]
 				if !ok {
-					return nil, nil, fmt.Errorf("resource %q: %w", rk, errcode.ErrInvalidTFStateFileMissingResourceID)
+					return fmt.Errorf("resource %q: %w", rk, errcode.ErrInvalidTFStateFileMissingResourceID)
 				}
 
 				name := extractResourceName(aux)
 
+				// can is the Canonical of the resource and ican
+				// the one of the instance, which only has the key
+				// if all the instances are displayed
+				can := prefixWithState(s.Name, prefixWithModule(m.Addr.String(), rk))
+				ican := can
+				if opt.Instances == InstanceAll && id != nil {
+					ican += id.String()
+				}
+
+				if isGroup {
+					gr := &graph.Group{
+						ID:        uuid.NewV4().String(),
+						Canonical: ican,
+						Name:      name,
+						TFID:      tfid.(string),
+						Resource:  *res,
+					}
+
+					err = lg.g.AddGroup(gr)
+					if err != nil {
+						return err
+					}
+
+					lg.groupCfg[gr.ID] = aux
+					continue
+				}
+
+				ins := nodeInstance{
+					Canonical: ican,
+					Name:      name,
+					TFID:      tfid.(string),
+					Config:    aux,
+				}
+				if rn != nil {
+					lg.instances[rn.ID] = append(lg.instances[rn.ID], ins)
+					continue
+				}
+
+				// The resource is one Node, from the first
+				// instance, and with InstanceAll it's expanded
+				// to the instances at the end of processGraph
 				n := &graph.Node{
 					ID:        uuid.NewV4().String(),
-					Canonical: prefixWithModule(m.Addr.String(), rk),
+					Canonical: can,
 					Name:      name,
 					TFID:      tfid.(string),
 					Resource:  *res,
 				}
 
-				err = g.AddNode(n)
+				if opt.Instances == InstanceCollapse {
+					n.Instances = len(rv.Instances)
+				}
+
+				err = lg.g.AddNode(n)
 				if err != nil {
-					return nil, nil, err
+					return err
 				}
 
-				nodeCanIDs[n.Canonical] = append(nodeCanIDs[n.Canonical], n.ID)
-				nodeIDEdges[n.ID] = deps
-				cfg[n.ID] = aux
+				if ican != can {
+					rn = n
+					lg.instances[n.ID] = []nodeInstance{ins}
+				}
+
+				lg.nodeCanIDs[can] = append(lg.nodeCanIDs[can], n.ID)
+				lg.nodeIDEdges[n.ID] = deps
+				lg.cfg[n.ID] = aux
+				lg.nodeStates[n.ID] = s.Name
 			}
 		}
 	}
 
-	for sourceID, edges := range nodeIDEdges {
+	return nil
+}
+
+// processGraph adds the Edges between the resources of the lg and
+// applies the logic of the Providers to it, returning the graph.Graph
+// and the config of each element
+func processGraph(lg *loadedGraph, opt Options) (*graph.Graph, map[string]interface{}, error) {
+	g := lg.g
+
+	for sourceID, edges := range lg.nodeIDEdges {
 		edgeIDs := make([]string, 0)
-		for _, e := range edges {
-			if IDs, ok := nodeCanIDs[e]; ok {
+		for _, e := range followExcluded(edges, lg.excluded) {
+			if IDs, ok := lg.nodeCanIDs[e]; ok {
 				edgeIDs = append(edgeIDs, IDs...)
 			}
 		}
 
 		for _, targetID := range edgeIDs {
+			// It could depend on itself
+			// through an excluded resource
+			if targetID == sourceID {
+				continue
+			}
+
 			err := g.AddEdge(&graph.Edge{
 				ID:     uuid.NewV4().String(),
 				Source: sourceID,
 				Target: targetID,
 			})
 			if err != nil {
+				// If the edge already exists we can ignore it
+				if errors.Is(err, errcode.ErrGraphAlreadyExistsEdge) {
+					continue
+				}
 				return nil, nil, err
 			}
 		}
 	}
+
+	if opt.Groups {
+		err := assignGroups(g, lg.cfg, lg.groupCfg, opt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// call the preprocess method for each
 	// TF provider in the file
-	if err := preprocess(g, cfg, opt); err != nil {
+	if err := preprocess(g, lg.cfg, opt); err != nil {
 		return nil, nil, err
 	}
 
@@ -190,7 +445,7 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 		g.Clean()
 	}
 
-	err = fixEdges(g, cfg, opt)
+	err := fixEdges(g, lg.cfg, opt)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -202,20 +457,132 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 		}
 	}
 
+	err = expandInstances(lg, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if opt.Clean {
 		err = cleanHangingEdges(g, opt)
 		if err != nil {
 			return nil, nil, err
 		}
+
+		g.CleanGroups()
 	}
 
-	endCfg, err := buildConfig(g, cfg, nodeCanIDs)
+	endCfg, err := buildConfig(g, lg.cfg, lg.nodeCanIDs)
 	if err != nil {
 		return nil, nil, err
 	}
 	return g, endCfg, nil
 }
 
+// linkStates adds an Edge from each Node to the Nodes of other
+// TFStates which TFID is on its config, as the dependencies
+// are only known between the resources of the same TFState
+func linkStates(lg *loadedGraph) error {
+	// tfidNodes holds the graph.Node.TFID -> []*graph.Node
+	tfidNodes := make(map[string][]*graph.Node)
+	for _, n := range lg.g.Nodes {
+		if n.TFID != "" {
+			tfidNodes[n.TFID] = append(tfidNodes[n.TFID], n)
+		}
+	}
+
+	for _, n := range lg.g.Nodes {
+		for _, v := range referenceValues("", lg.cfg[n.ID]) {
+			for _, tn := range tfidNodes[v] {
+				if tn.ID == n.ID || lg.nodeStates[tn.ID] == lg.nodeStates[n.ID] {
+					continue
+				}
+
+				err := lg.g.AddEdge(&graph.Edge{
+					ID:     uuid.NewV4().String(),
+					Source: n.ID,
+					Target: tn.ID,
+				})
+				if err != nil {
+					// If the edge already exists we can ignore it
+					if errors.Is(err, errcode.ErrGraphAlreadyExistsEdge) {
+						continue
+					}
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// minReferenceLen is the minimum length of a value to be
+// considered a reference to a resource from another TFState,
+// so short and generic ones like 'default' are not linked
+const minReferenceLen = 8
+
+// referenceValues returns the string values of the v, and of all
+// the lists and maps it has, recursively, that may reference other
+// resources: the ones of the attributes with IDs or ARNs, like
+// 'vpc_id', 'subnet_ids' or 'security_groups', and the ARNs. The
+// k is the key of the v on its parent
+func referenceValues(k string, v interface{}) []string {
+	res := make([]string, 0)
+	switch vv := v.(type) {
+	case string:
+		if len(vv) < minReferenceLen {
+			break
+		}
+		if isReferenceKey(k) || strings.HasPrefix(vv, "arn:") {
+			res = append(res, vv)
+		}
+	case []interface{}:
+		// The elements of the list have the key of it
+		for _, i := range vv {
+			res = append(res, referenceValues(k, i)...)
+		}
+	case map[string]interface{}:
+		for ik, i := range vv {
+			res = append(res, referenceValues(ik, i)...)
+		}
+	}
+	return res
+}
+
+// isReferenceKey checks if the attribute k holds IDs or ARNs
+// of other resources. The own 'id' of the resource is not one
+func isReferenceKey(k string) bool {
+	for _, s := range []string{"_id", "_ids", "_arn", "_arns"} {
+		if strings.HasSuffix(k, s) {
+			return true
+		}
+	}
+	switch k {
+	case "security_groups", "subnets", "instances", "network", "subnetwork":
+		return true
+	}
+	return false
+}
+
+// configValues returns all the string values of the v
+// and of all the lists and maps it has, recursively
+func configValues(v interface{}) []string {
+	res := make([]string, 0)
+	switch vv := v.(type) {
+	case string:
+		res = append(res, vv)
+	case []interface{}:
+		for _, i := range vv {
+			res = append(res, configValues(i)...)
+		}
+	case map[string]interface{}:
+		for _, i := range vv {
+			res = append(res, configValues(i)...)
+		}
+	}
+	return res
+}
+
 func extractResourceName(attrs map[string]interface{}) string {
 	if t, ok := attrs["tags"]; ok && t != nil {
 		if m, ok := t.(map[string]interface{}); ok {
@@ -437,7 +804,8 @@ func buildConfig(g *graph.Graph, cfg map[string]map[string]interface{}, nodeCanI
 			if !ok {
 				return nil, fmt.Errorf("could not find the ID of the canonical %q: %w", can, errcode.ErrInvalidTFStateFile)
 			}
-			// We only do the ID 0 for now
+			// The Canonicals of the Nodes are the ones of the
+			// instances, so they only have one ID
 			c, ok := cfg[ids[0]]
 			if !ok {
 				return nil, fmt.Errorf("could not find config of the Node %q: %w", can, errcode.ErrInvalidTFStateFile)
@@ -459,8 +827,9 @@ func buildConfig(g *graph.Graph, cfg map[string]map[string]interface{}, nodeCanI
 	return endCfg, nil
 }
 
-// reVariable matches ${aws_security_group.front.id}
-var reVariable = regexp.MustCompile(`\$\{(?P<type>[^\.][a-z0-9-_]+)\.(?P<name>[^\.][a-z0-9-_]+)\.(?P<attr>[a-z0-9-_]+)\}`)
+// reVariable matches ${aws_security_group.front.id} and, from the
+// resources on modules, ${module.front.aws_security_group.front.id}
+var reVariable = regexp.MustCompile(`\$\{(?P<type>(?:module\.[a-z0-9-_]+\.)*[^\.][a-z0-9-_]+)\.(?P<name>[^\.][a-z0-9-_]+)\.(?P<attr>[a-z0-9-_]+)\}`)
 
 // fixEdges tries to fix the direction of the edges that was done based on the 'depends_on'
 // to something more Provider dependent by reading the actual config.
@@ -786,6 +1155,17 @@ func mutate(g *graph.Graph, opt Options) error {
 	return mutate(g, opt)
 }
 
+// instanceKeys returns the keys of the instances that have to be
+// used depending on the mode, sorted so the first one is the '[0]'
+func instanceKeys(instances map[addrs.InstanceKey]*states.ResourceInstance, mode InstanceMode) []addrs.InstanceKey {
+	keys := make([]addrs.InstanceKey, 0, len(instances))
+	for k := range instances {
+		keys = append(keys, k)
+	}
+
+	return selectInstanceKeys(keys, mode)
+}
+
 func instanceCurrentDependenciesToString(deps []addrs.ConfigResource) []string {
 	res := make([]string, 0, len(deps))
 	for _, d := range deps {
@@ -806,7 +1186,7 @@ func getProviderAndResource(rk string, opt Options) (provider.Provider, string,
 	if opt.Raw {
 		pv = provider.RawProvider{}
 
-		rss := strings.Split(rk, ".")
+		rss := strings.Split(provider.TrimInstanceKey(rk), ".")
 		if len(rss) > 1 {
 			rs = rss[len(rss)-2]
 		} else {
@@ -894,3 +1274,12 @@ func prefixWithModule(moduleName, resource string) string {
 	}
 	return resource
 }
+
+// prefixWithState returns the can namespaced with the
+// name of the TFState in which it is, if it has one
+func prefixWithState(name, can string) string {
+	if name != "" {
+		can = fmt.Sprintf("%s.%s", name, can)
+	}
+	return can
+}
@@ -1,8 +1,10 @@
 package generate_test
 
 import (
+	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -146,6 +148,63 @@ func TestFromState_AWS(t *testing.T) {
 		assertEqualGraph(t, eg, g, cfg)
 	})
 
+	t.Run("SuccessShowSG", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_show_state_sg.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "module.lemp.aws_lb.tQBgz",
+					Name:      "5d7daaa0-68a7-4ca5-a491-3f78c102d562",
+				},
+				&graph.Node{
+					Canonical: "module.lemp.aws_launch_template.vIkyE",
+					Name:      "lt-08e7a3cd65dc2457c",
+				},
+				&graph.Node{
+					Canonical: "module.lemp.aws_db_instance.Cpbzf",
+					Name:      "sample-lemp-rds-prod",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/443->443",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "im_out.tcp/443->443",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "module.lemp.aws_lb.tQBgz",
+					Target:     "module.lemp.aws_launch_template.vIkyE",
+					Canonicals: []string{"module.lemp.aws_security_group.rZnGI", "module.lemp.aws_security_group.YPHPR"},
+				},
+				&graph.Edge{
+					Source:     "module.lemp.aws_launch_template.vIkyE",
+					Target:     "module.lemp.aws_db_instance.Cpbzf",
+					Canonicals: []string{"module.lemp.aws_security_group.YPHPR", "module.lemp.aws_security_group.LHwFh"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+
 	t.Run("SuccessSGR", func(t *testing.T) {
 		src, err := ioutil.ReadFile("./testdata/aws_state_sgr.json")
 		require.NoError(t, err)
@@ -293,6 +352,54 @@ func TestFromState_AWS(t *testing.T) {
 		assertEqualGraph(t, eg, g, cfg)
 	})
 
+	t.Run("SuccessGroups", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_groups.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, Groups: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "front",
+					Group:     "aws_subnet.public",
+				},
+				&graph.Node{
+					Canonical: "aws_instance.web",
+					Name:      "web",
+					Group:     "aws_subnet.public",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source: "aws_lb.front",
+					Target: "aws_instance.web",
+					Canonicals: []string{
+						"aws_security_group.front",
+						"aws_security_group.web",
+					},
+				},
+			},
+			Groups: []*graph.Group{
+				&graph.Group{
+					Canonical: "aws_vpc.main",
+					Name:      "main",
+				},
+				&graph.Group{
+					Canonical: "aws_subnet.public",
+					Name:      "public",
+					Parent:    "aws_vpc.main",
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+
 	t.Run("WithCount", func(t *testing.T) {
 		src, err := ioutil.ReadFile("./testdata/aws_state_with_count.json")
 		require.NoError(t, err)
@@ -303,6 +410,253 @@ func TestFromState_AWS(t *testing.T) {
 		require.NotNil(t, cfg)
 		assert.Len(t, g.Nodes, 2)
 	})
+	t.Run("SuccessInstancesAll", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_instances.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, Instances: generate.InstanceAll})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		// Each instance has the Edges of the resource,
+		// as they all share the same SGs
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "front",
+				},
+				&graph.Node{
+					Canonical: "aws_instance.web[0]",
+					Name:      "web-0",
+				},
+				&graph.Node{
+					Canonical: "aws_instance.web[1]",
+					Name:      "web-1",
+				},
+				&graph.Node{
+					Canonical: `aws_instance.worker["a.b"]`,
+					Name:      "worker-a",
+				},
+				&graph.Node{
+					Canonical: `aws_instance.worker["c"]`,
+					Name:      "worker-c",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source: "aws_lb.front",
+					Target: "aws_instance.web[0]",
+					Canonicals: []string{
+						"aws_security_group.front",
+						"aws_security_group.web",
+					},
+				},
+				&graph.Edge{
+					Source: "aws_lb.front",
+					Target: "aws_instance.web[1]",
+					Canonicals: []string{
+						"aws_security_group.front",
+						"aws_security_group.web",
+					},
+				},
+				&graph.Edge{
+					Source: "aws_instance.web[0]",
+					Target: `aws_instance.worker["a.b"]`,
+					Canonicals: []string{
+						"aws_security_group.web",
+						"aws_security_group.worker",
+					},
+				},
+				&graph.Edge{
+					Source: "aws_instance.web[0]",
+					Target: `aws_instance.worker["c"]`,
+					Canonicals: []string{
+						"aws_security_group.web",
+						"aws_security_group.worker",
+					},
+				},
+				&graph.Edge{
+					Source: "aws_instance.web[1]",
+					Target: `aws_instance.worker["a.b"]`,
+					Canonicals: []string{
+						"aws_security_group.web",
+						"aws_security_group.worker",
+					},
+				},
+				&graph.Edge{
+					Source: "aws_instance.web[1]",
+					Target: `aws_instance.worker["c"]`,
+					Canonicals: []string{
+						"aws_security_group.web",
+						"aws_security_group.worker",
+					},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessInstancesAllReferenced", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_instances_eip.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, Instances: generate.InstanceAll})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		// Each aws_eip has the ID of one of the instances
+		// so they are only connected to that one
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "aws_instance.web[0]",
+					Name:      "web-0",
+				},
+				&graph.Node{
+					Canonical: "aws_instance.web[1]",
+					Name:      "web-1",
+				},
+				&graph.Node{
+					Canonical: "aws_eip.web[0]",
+					Name:      "web-0",
+				},
+				&graph.Node{
+					Canonical: "aws_eip.web[1]",
+					Name:      "web-1",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "aws_eip.web[0]",
+					Target:     "aws_instance.web[0]",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "aws_eip.web[1]",
+					Target:     "aws_instance.web[1]",
+					Canonicals: []string(nil),
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessInstancesCollapse", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_instances.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, Instances: generate.InstanceCollapse})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "aws_lb.front",
+					Name:      "front",
+					Instances: 1,
+				},
+				&graph.Node{
+					Canonical: "aws_instance.web",
+					Name:      "web-0",
+					Instances: 2,
+				},
+				&graph.Node{
+					Canonical: "aws_instance.worker",
+					Name:      "worker-a",
+					Instances: 2,
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source: "aws_lb.front",
+					Target: "aws_instance.web",
+					Canonicals: []string{
+						"aws_security_group.front",
+						"aws_security_group.web",
+					},
+				},
+				&graph.Edge{
+					Source: "aws_instance.web",
+					Target: "aws_instance.worker",
+					Canonicals: []string{
+						"aws_security_group.web",
+						"aws_security_group.worker",
+					},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessInstancesShowState", func(t *testing.T) {
+		// The 'terraform show -json' has the same resources as
+		// the statefile so the same instances have to be used
+		state, err := ioutil.ReadFile("./testdata/aws_state_instances_count.json")
+		require.NoError(t, err)
+
+		show, err := ioutil.ReadFile("./testdata/aws_show_state_instances_count.json")
+		require.NoError(t, err)
+
+		nodes := func(g *graph.Graph) map[string]string {
+			res := make(map[string]string)
+			for _, n := range g.Nodes {
+				res[n.Canonical] = n.Name
+			}
+			return res
+		}
+
+		tests := []struct {
+			Mode  generate.InstanceMode
+			Nodes map[string]string
+		}{
+			{
+				// The 'for_each' ones are ignored and
+				// there is no '[0]' on the 'count' one
+				Mode: generate.InstanceFirst,
+				Nodes: map[string]string{
+					"aws_lb.front": "front",
+				},
+			},
+			{
+				// The first one is the '[2]' as the
+				// keys of 'count' are sorted by number
+				Mode: generate.InstanceCollapse,
+				Nodes: map[string]string{
+					"aws_lb.front":        "front",
+					"aws_instance.web":    "web-2",
+					"aws_instance.worker": "worker-a",
+				},
+			},
+			{
+				Mode: generate.InstanceAll,
+				Nodes: map[string]string{
+					"aws_lb.front":               "front",
+					"aws_instance.web[2]":        "web-2",
+					"aws_instance.web[10]":       "web-10",
+					`aws_instance.worker["a.b"]`: "worker-a",
+					`aws_instance.worker["c"]`:   "worker-c",
+				},
+			},
+		}
+		for _, tt := range tests {
+			opt := generate.Options{Clean: true, Connections: true, Instances: tt.Mode}
+
+			sg, _, err := generate.FromState(state, opt)
+			require.NoError(t, err)
+
+			shg, _, err := generate.FromState(show, opt)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.Nodes, nodes(sg), tt.Mode.String())
+			assert.Equal(t, tt.Nodes, nodes(shg), tt.Mode.String())
+			assert.Len(t, shg.Edges, len(sg.Edges), tt.Mode.String())
+		}
+	})
 	t.Run("Version3", func(t *testing.T) {
 		src, err := ioutil.ReadFile("./testdata/version_3_state.json")
 		require.NoError(t, err)
@@ -715,3 +1069,383 @@ func TestFromState_Azure(t *testing.T) {
 		assertEqualGraph(t, eg, g, cfg)
 	})
 }
+
+func TestFromStates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		network, err := ioutil.ReadFile("./testdata/aws_state_network.json")
+		require.NoError(t, err)
+
+		app, err := ioutil.ReadFile("./testdata/aws_state_app.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromStates(map[string]json.RawMessage{"network": network, "app": app}, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+				&graph.Node{
+					Canonical: "network.aws_lb.front",
+					Name:      "front",
+				},
+				&graph.Node{
+					Canonical: "app.aws_instance.web",
+					Name:      "web",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "network.aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "network.aws_lb.front",
+					Target:     "app.aws_instance.web",
+					Canonicals: []string{"network.aws_security_group.lb", "app.aws_security_group.web"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessUnrelatedValues", func(t *testing.T) {
+		// The 'description' of the aws_security_group.web is the ID of
+		// the network aws_security_group.lb and the 'vpc_id' of it is the
+		// ID of the app aws_vpc.default but none of them are references
+		network, err := ioutil.ReadFile("./testdata/aws_state_network.json")
+		require.NoError(t, err)
+
+		app, err := ioutil.ReadFile("./testdata/aws_state_app_unrelated.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromStates(map[string]json.RawMessage{"network": network, "app": app}, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+				&graph.Node{
+					Canonical: "network.aws_lb.front",
+					Name:      "front",
+				},
+				&graph.Node{
+					Canonical: "app.aws_instance.web",
+					Name:      "web",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "network.aws_lb.front",
+					Canonicals: []string(nil),
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("ErrInvalidTFStateVersion", func(t *testing.T) {
+		network, err := ioutil.ReadFile("./testdata/aws_state_network.json")
+		require.NoError(t, err)
+
+		invalid, err := ioutil.ReadFile("./testdata/invalid_version_state.json")
+		require.NoError(t, err)
+
+		_, _, err = generate.FromStates(map[string]json.RawMessage{"network": network, "app": invalid}, generate.Options{Clean: true, Connections: true})
+		assert.True(t, errors.Is(err, errcode.ErrInvalidTFStateVersion))
+	})
+}
+
+func TestFromState_RemoteState(t *testing.T) {
+	eg := &graph.Graph{
+		Nodes: []*graph.Node{
+			&graph.Node{
+				Canonical: "im_out.tcp/80->80",
+			},
+			&graph.Node{
+				Canonical: "network.aws_lb.front",
+				Name:      "front",
+			},
+			&graph.Node{
+				Canonical: "aws_instance.web",
+				Name:      "web",
+			},
+		},
+		Edges: []*graph.Edge{
+			&graph.Edge{
+				Source:     "im_out.tcp/80->80",
+				Target:     "network.aws_lb.front",
+				Canonicals: []string(nil),
+			},
+			&graph.Edge{
+				Source:     "network.aws_lb.front",
+				Target:     "aws_instance.web",
+				Canonicals: []string{"network.aws_security_group.lb", "aws_security_group.web"},
+			},
+		},
+	}
+
+	t.Run("SuccessBackendConfig", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_app_remote.json")
+		require.NoError(t, err)
+
+		network, err := ioutil.ReadFile("./testdata/aws_state_network.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, RemoteStates: map[string]json.RawMessage{"network/terraform.tfstate": network}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessName", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_app_remote.json")
+		require.NoError(t, err)
+
+		network, err := ioutil.ReadFile("./testdata/aws_state_network.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, RemoteStates: map[string]json.RawMessage{"network": network}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("ErrInvalidTFStateVersion", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_app_remote.json")
+		require.NoError(t, err)
+
+		invalid, err := ioutil.ReadFile("./testdata/invalid_version_state.json")
+		require.NoError(t, err)
+
+		_, _, err = generate.FromState(src, generate.Options{Clean: true, Connections: true, RemoteStates: map[string]json.RawMessage{"network": invalid}})
+		assert.True(t, errors.Is(err, errcode.ErrInvalidTFStateVersion))
+	})
+}
+
+func TestFromState_Filter(t *testing.T) {
+	src, err := ioutil.ReadFile("./testdata/aws_state_sg.json")
+	require.NoError(t, err)
+
+	t.Run("SuccessExclude", func(t *testing.T) {
+		f, err := generate.ParseFilter("type:aws_db_instance")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Exclude: []generate.Filter{f}})
+		require.NoError(t, err)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "module.lemp.aws_lb.tQBgz",
+					Name:      "5d7daaa0-68a7-4ca5-a491-3f78c102d562",
+				},
+				&graph.Node{
+					Canonical: "module.lemp.aws_launch_template.vIkyE",
+					Name:      "lt-08e7a3cd65dc2457c",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/443->443",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "im_out.tcp/443->443",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "module.lemp.aws_lb.tQBgz",
+					Target:     "module.lemp.aws_launch_template.vIkyE",
+					Canonicals: []string{"module.lemp.aws_security_group.rZnGI", "module.lemp.aws_security_group.YPHPR"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessExcludeEdges", func(t *testing.T) {
+		// The aws_security_group are the Edges of the Provider
+		// so they are never excluded and the Nodes keep the
+		// connections through them
+		f, err := generate.ParseFilter("type:aws_security_group")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Exclude: []generate.Filter{f}})
+		require.NoError(t, err)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "module.lemp.aws_lb.tQBgz",
+					Name:      "5d7daaa0-68a7-4ca5-a491-3f78c102d562",
+				},
+				&graph.Node{
+					Canonical: "module.lemp.aws_launch_template.vIkyE",
+					Name:      "lt-08e7a3cd65dc2457c",
+				},
+				&graph.Node{
+					Canonical: "module.lemp.aws_db_instance.Cpbzf",
+					Name:      "sample-lemp-rds-prod",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/443->443",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "im_out.tcp/443->443",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "module.lemp.aws_lb.tQBgz",
+					Target:     "module.lemp.aws_launch_template.vIkyE",
+					Canonicals: []string{"module.lemp.aws_security_group.rZnGI", "module.lemp.aws_security_group.YPHPR"},
+				},
+				&graph.Edge{
+					Source:     "module.lemp.aws_launch_template.vIkyE",
+					Target:     "module.lemp.aws_db_instance.Cpbzf",
+					Canonicals: []string{"module.lemp.aws_security_group.YPHPR", "module.lemp.aws_security_group.LHwFh"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessIncludeEdges", func(t *testing.T) {
+		flts := make([]generate.Filter, 0)
+		for _, f := range []string{"type:aws_lb", "type:aws_launch_template"} {
+			flt, err := generate.ParseFilter(f)
+			require.NoError(t, err)
+			flts = append(flts, flt)
+		}
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Include: flts})
+		require.NoError(t, err)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				&graph.Node{
+					Canonical: "module.lemp.aws_lb.tQBgz",
+					Name:      "5d7daaa0-68a7-4ca5-a491-3f78c102d562",
+				},
+				&graph.Node{
+					Canonical: "module.lemp.aws_launch_template.vIkyE",
+					Name:      "lt-08e7a3cd65dc2457c",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/443->443",
+				},
+				&graph.Node{
+					Canonical: "im_out.tcp/80->80",
+				},
+			},
+			Edges: []*graph.Edge{
+				&graph.Edge{
+					Source:     "im_out.tcp/80->80",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "im_out.tcp/443->443",
+					Target:     "module.lemp.aws_lb.tQBgz",
+					Canonicals: []string(nil),
+				},
+				&graph.Edge{
+					Source:     "module.lemp.aws_lb.tQBgz",
+					Target:     "module.lemp.aws_launch_template.vIkyE",
+					Canonicals: []string{"module.lemp.aws_security_group.rZnGI", "module.lemp.aws_security_group.YPHPR"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessExcludeMerged", func(t *testing.T) {
+		// The resources that depended on the excluded
+		// one depend on the dependencies of it
+		f, err := generate.ParseFilter("module.lemp.aws_security_group.YPHPR")
+		require.NoError(t, err)
+
+		g, _, err := generate.FromState(src, generate.Options{Raw: true, Clean: true, Exclude: []generate.Filter{f}})
+		require.NoError(t, err)
+
+		_, err = g.GetNodeByCanonical("module.lemp.aws_security_group.YPHPR")
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundNode))
+
+		for _, can := range []string{"module.lemp.aws_launch_template.vIkyE", "module.lemp.aws_security_group.LHwFh"} {
+			nodes, err := g.Neighbours(can, graph.DirectionOut)
+			require.NoError(t, err)
+			require.Len(t, nodes, 1, can)
+			assert.Equal(t, "module.lemp.aws_security_group.rZnGI", nodes[0].Canonical, can)
+		}
+	})
+	t.Run("SuccessModuleKey", func(t *testing.T) {
+		// The key of the module has a '.' so the
+		// module path can not be split from the Canonical
+		src, err := ioutil.ReadFile("./testdata/aws_state_sg_module_key.json")
+		require.NoError(t, err)
+
+		f, err := generate.ParseFilter(`module:module.lemp["a.b"]`)
+		require.NoError(t, err)
+
+		g, _, err := generate.FromState(src, generate.Options{Raw: true, Include: []generate.Filter{f}})
+		require.NoError(t, err)
+		assert.Len(t, g.Nodes, 6)
+		for _, n := range g.Nodes {
+			assert.True(t, strings.HasPrefix(n.Canonical, `module.lemp["a.b"].`), n.Canonical)
+		}
+
+		g, _, err = generate.FromState(src, generate.Options{Raw: true, Exclude: []generate.Filter{f}})
+		require.NoError(t, err)
+		require.Len(t, g.Nodes, 1)
+		assert.Equal(t, "module.ec2.aws_security_group_rule.czNph", g.Nodes[0].Canonical)
+	})
+	t.Run("SuccessInclude", func(t *testing.T) {
+		flts := make([]generate.Filter, 0)
+		for _, f := range []string{"type:aws_db_instance", "*.aws_security_group.rZnGI"} {
+			flt, err := generate.ParseFilter(f)
+			require.NoError(t, err)
+			flts = append(flts, flt)
+		}
+
+		g, _, err := generate.FromState(src, generate.Options{Raw: true, Clean: true, Include: flts})
+		require.NoError(t, err)
+
+		// The aws_security_group between them are not
+		// included so they are connected through them
+		nodes, err := g.Neighbours("module.lemp.aws_db_instance.Cpbzf", graph.DirectionOut)
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		assert.Equal(t, "module.lemp.aws_security_group.rZnGI", nodes[0].Canonical)
+		assert.Len(t, g.Nodes, 2)
+	})
+}
@@ -0,0 +1,49 @@
+variable "allowed_cidrs" {
+  default = ["0.0.0.0/0"]
+}
+
+variable "http_port" {
+  default = 80
+}
+
+locals {
+  lb_security_group_id    = aws_security_group.lb-front.id
+  front_security_group_id = aws_security_group.front.id
+}
+
+resource "aws_lb" "front" {
+  name            = "some name"
+  security_groups = [local.lb_security_group_id]
+}
+
+resource "aws_security_group" "lb-front" {
+  name        = "some name"
+  description = "Front "
+
+  ingress {
+    from_port   = var.http_port
+    to_port     = var.http_port
+    protocol    = "tcp"
+    cidr_blocks = var.allowed_cidrs
+  }
+}
+
+resource "aws_launch_template" "front" {
+  name_prefix = "name"
+
+  network_interfaces {
+    security_groups = [local.front_security_group_id]
+  }
+}
+
+resource "aws_security_group" "front" {
+  name        = "front"
+  description = "Front"
+
+  ingress {
+    from_port       = 80
+    to_port         = 80
+    protocol        = "tcp"
+    security_groups = [local.lb_security_group_id]
+  }
+}
@@ -0,0 +1,48 @@
+# ALB
+
+resource "aws_lb" "front" {
+  name            = "some name"
+  security_groups = [aws_security_group.lb-front.id]
+}
+
+resource "aws_security_group" "lb-front" {
+  name        = "some name"
+  description = "Front "
+
+  ingress {
+    from_port   = 80
+    to_port     = 80
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+# Front
+
+module "front" {
+  source = "./modules/front"
+
+  lb_security_group_id = aws_security_group.lb-front.id
+}
+
+# RDS
+
+module "rds" {
+  source = "./modules/rds"
+
+  front_security_group_id = module.front.security_group_id
+}
+
+# Remote modules are ignored
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "3.0.0"
+}
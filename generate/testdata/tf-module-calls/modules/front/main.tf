@@ -0,0 +1,25 @@
+variable "lb_security_group_id" {}
+
+resource "aws_launch_template" "front" {
+  name_prefix = "name"
+
+  network_interfaces {
+    security_groups = [aws_security_group.front.id]
+  }
+}
+
+resource "aws_security_group" "front" {
+  name        = "front"
+  description = "Front"
+
+  ingress {
+    from_port       = 80
+    to_port         = 80
+    protocol        = "tcp"
+    security_groups = [var.lb_security_group_id]
+  }
+}
+
+output "security_group_id" {
+  value = aws_security_group.front.id
+}
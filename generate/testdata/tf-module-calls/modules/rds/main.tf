@@ -0,0 +1,19 @@
+variable "front_security_group_id" {}
+
+resource "aws_security_group" "rds" {
+  name        = "rds"
+  description = "rds"
+
+  ingress {
+    from_port       = 3306
+    to_port         = 3306
+    protocol        = "tcp"
+    security_groups = [var.front_security_group_id]
+  }
+}
+
+resource "aws_db_instance" "application" {
+  identifier = "rds"
+
+  vpc_security_group_ids = [aws_security_group.rds.id]
+}
@@ -0,0 +1,29 @@
+package graph
+
+// Action is the change planned for the
+// resource of a Node, it's only known when
+// the Graph is generated from a plan, or
+// the change of a Node or Edge on a Diff
+type Action int
+
+//go:generate ./../bin/enumer -type=Action -transform=lower -trimprefix=Action -output=action_string.go
+
+// List of all Actions
+const (
+	// ActionNone means that no change is planned
+	// or that it's not known, like from a TFState
+	ActionNone Action = iota
+
+	// ActionCreate means that the resource will be created
+	ActionCreate
+
+	// ActionUpdate means that the resource will be updated in-place
+	ActionUpdate
+
+	// ActionDelete means that the resource will be destroyed
+	ActionDelete
+
+	// ActionReplace means that the resource will
+	// be destroyed and created again
+	ActionReplace
+)
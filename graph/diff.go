@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Changes holds the differences between
+// two Graphs as returned by Diff
+type Changes struct {
+	// Graph has the Nodes and Edges of both Graphs with
+	// the Action of the change: ActionCreate for the added
+	// ones, ActionDelete for the removed, ActionUpdate for
+	// the changed and ActionNone for the rest
+	Graph *Graph
+
+	AddedNodes   []*Node
+	RemovedNodes []*Node
+	ChangedNodes []*Node
+
+	AddedEdges   []*Edge
+	RemovedEdges []*Edge
+	ChangedEdges []*Edge
+}
+
+// Diff returns the Changes from the Graph a to the Graph b. The Nodes
+// are matched by the Canonical and are changed if the TFID, the Name or
+// the Instances are different, and the Edges are matched by the Canonicals
+// of the Source and Target and are changed if the Canonicals are different.
+// On the Changes.Graph the ID of the Nodes is the Canonical and the one
+// of the Edges is 'Source->Target', the Groups are not included
+func Diff(a, b *Graph) (*Changes, error) {
+	c := &Changes{
+		Graph: New(),
+	}
+
+	for _, bn := range b.Nodes {
+		n := diffNode(bn)
+		an, err := a.GetNodeByCanonical(bn.Canonical)
+		if err != nil {
+			n.Action = ActionCreate
+			c.AddedNodes = append(c.AddedNodes, n)
+		} else if an.TFID != bn.TFID || an.Name != bn.Name || an.Instances != bn.Instances {
+			n.Action = ActionUpdate
+			c.ChangedNodes = append(c.ChangedNodes, n)
+		}
+
+		err = c.Graph.AddNode(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, an := range a.Nodes {
+		if _, err := b.GetNodeByCanonical(an.Canonical); err == nil {
+			continue
+		}
+
+		n := diffNode(an)
+		n.Action = ActionDelete
+		c.RemovedNodes = append(c.RemovedNodes, n)
+
+		err := c.Graph.AddNode(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	aEdges, err := edgesByCanonicals(a)
+	if err != nil {
+		return nil, err
+	}
+
+	bEdges, err := edgesByCanonicals(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, be := range b.Edges {
+		e, err := diffEdge(b, be)
+		if err != nil {
+			return nil, err
+		}
+
+		if ae, ok := aEdges[e.ID]; !ok {
+			e.Action = ActionCreate
+			c.AddedEdges = append(c.AddedEdges, e)
+		} else if !equalCanonicals(ae.Canonicals, be.Canonicals) {
+			e.Action = ActionUpdate
+			c.ChangedEdges = append(c.ChangedEdges, e)
+		}
+
+		err = c.Graph.AddEdge(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ae := range a.Edges {
+		e, err := diffEdge(a, ae)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := bEdges[e.ID]; ok {
+			continue
+		}
+
+		e.Action = ActionDelete
+		c.RemovedEdges = append(c.RemovedEdges, e)
+
+		err = c.Graph.AddEdge(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// HasChanges checks if there is any change
+func (c *Changes) HasChanges() bool {
+	return len(c.AddedNodes) != 0 || len(c.RemovedNodes) != 0 || len(c.ChangedNodes) != 0 ||
+		len(c.AddedEdges) != 0 || len(c.RemovedEdges) != 0 || len(c.ChangedEdges) != 0
+}
+
+// diffNode returns a copy of the n to be used on
+// the Changes.Graph, with the Canonical as ID
+func diffNode(n *Node) *Node {
+	dn := *n
+	dn.ID = n.Canonical
+	dn.Group = ""
+	dn.Action = ActionNone
+	return &dn
+}
+
+// diffEdge returns a copy of the e of the g to be used on the
+// Changes.Graph, with the Source and Target being the Canonicals
+func diffEdge(g *Graph, e *Edge) (*Edge, error) {
+	src, err := g.GetNodeByID(e.Source)
+	if err != nil {
+		return nil, fmt.Errorf("source of edge %q: %w", e.ID, err)
+	}
+
+	tgt, err := g.GetNodeByID(e.Target)
+	if err != nil {
+		return nil, fmt.Errorf("target of edge %q: %w", e.ID, err)
+	}
+
+	de := &Edge{
+		ID:     fmt.Sprintf("%s->%s", src.Canonical, tgt.Canonical),
+		Source: src.Canonical,
+		Target: tgt.Canonical,
+	}
+	de.AddCanonicals(e.Canonicals...)
+
+	return de, nil
+}
+
+// edgesByCanonicals returns the Edges of the g
+// by the 'Source->Target' Canonicals
+func edgesByCanonicals(g *Graph) (map[string]*Edge, error) {
+	res := make(map[string]*Edge, len(g.Edges))
+	for _, e := range g.Edges {
+		de, err := diffEdge(g, e)
+		if err != nil {
+			return nil, err
+		}
+		res[de.ID] = e
+	}
+	return res, nil
+}
+
+// equalCanonicals checks if a and b have
+// the same Canonicals in any order
+func equalCanonicals(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sa := append([]string{}, a...)
+	sb := append([]string{}, b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+
+	return true
+}
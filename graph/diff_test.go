@@ -0,0 +1,87 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		a := graph.New()
+		require.NoError(t, a.AddNode(&graph.Node{ID: "a1", Canonical: "aws_lb.front", TFID: "lb-1"}))
+		require.NoError(t, a.AddNode(&graph.Node{ID: "a2", Canonical: "aws_instance.web", TFID: "i-1"}))
+		require.NoError(t, a.AddNode(&graph.Node{ID: "a3", Canonical: "aws_db_instance.db", TFID: "db-1"}))
+		require.NoError(t, a.AddEdge(&graph.Edge{ID: "ae1", Source: "a1", Target: "a2", Canonicals: []string{"aws_security_group.lb"}}))
+		require.NoError(t, a.AddEdge(&graph.Edge{ID: "ae2", Source: "a2", Target: "a3"}))
+
+		b := graph.New()
+		require.NoError(t, b.AddNode(&graph.Node{ID: "b1", Canonical: "aws_lb.front", TFID: "lb-1"}))
+		require.NoError(t, b.AddNode(&graph.Node{ID: "b2", Canonical: "aws_instance.web", TFID: "i-2"}))
+		require.NoError(t, b.AddNode(&graph.Node{ID: "b3", Canonical: "aws_elasticache_cluster.cache", TFID: "cache-1"}))
+		require.NoError(t, b.AddEdge(&graph.Edge{ID: "be1", Source: "b1", Target: "b2", Canonicals: []string{"aws_security_group.lb", "aws_security_group.web"}}))
+		require.NoError(t, b.AddEdge(&graph.Edge{ID: "be2", Source: "b2", Target: "b3"}))
+
+		c, err := graph.Diff(a, b)
+		require.NoError(t, err)
+		assert.True(t, c.HasChanges())
+
+		nodes := make(map[string]graph.Action)
+		for _, n := range c.Graph.Nodes {
+			assert.Equal(t, n.Canonical, n.ID)
+			nodes[n.Canonical] = n.Action
+		}
+		assert.Equal(t, map[string]graph.Action{
+			"aws_lb.front":                  graph.ActionNone,
+			"aws_instance.web":              graph.ActionUpdate,
+			"aws_elasticache_cluster.cache": graph.ActionCreate,
+			"aws_db_instance.db":            graph.ActionDelete,
+		}, nodes)
+
+		edges := make(map[string]graph.Action)
+		for _, e := range c.Graph.Edges {
+			edges[e.ID] = e.Action
+		}
+		assert.Equal(t, map[string]graph.Action{
+			"aws_lb.front->aws_instance.web":                  graph.ActionUpdate,
+			"aws_instance.web->aws_elasticache_cluster.cache": graph.ActionCreate,
+			"aws_instance.web->aws_db_instance.db":            graph.ActionDelete,
+		}, edges)
+
+		require.Len(t, c.AddedNodes, 1)
+		assert.Equal(t, "aws_elasticache_cluster.cache", c.AddedNodes[0].Canonical)
+		require.Len(t, c.RemovedNodes, 1)
+		assert.Equal(t, "aws_db_instance.db", c.RemovedNodes[0].Canonical)
+		require.Len(t, c.ChangedNodes, 1)
+		assert.Equal(t, "aws_instance.web", c.ChangedNodes[0].Canonical)
+
+		require.Len(t, c.AddedEdges, 1)
+		require.Len(t, c.RemovedEdges, 1)
+		require.Len(t, c.ChangedEdges, 1)
+		assert.Equal(t, []string{"aws_security_group.lb", "aws_security_group.web"}, c.ChangedEdges[0].Canonicals)
+
+		// The Graphs are not modified
+		assert.Equal(t, graph.ActionNone, b.Nodes[1].Action)
+		assert.Equal(t, "b2", b.Nodes[1].ID)
+	})
+	t.Run("SuccessNoChanges", func(t *testing.T) {
+		a := graph.New()
+		require.NoError(t, a.AddNode(&graph.Node{ID: "a1", Canonical: "aws_lb.front"}))
+		require.NoError(t, a.AddNode(&graph.Node{ID: "a2", Canonical: "aws_instance.web"}))
+		require.NoError(t, a.AddEdge(&graph.Edge{ID: "ae1", Source: "a1", Target: "a2", Canonicals: []string{"aws_security_group.lb", "aws_security_group.web"}}))
+
+		b := graph.New()
+		require.NoError(t, b.AddNode(&graph.Node{ID: "b2", Canonical: "aws_instance.web"}))
+		require.NoError(t, b.AddNode(&graph.Node{ID: "b1", Canonical: "aws_lb.front"}))
+		require.NoError(t, b.AddEdge(&graph.Edge{ID: "be1", Source: "b1", Target: "b2", Canonicals: []string{"aws_security_group.web", "aws_security_group.lb"}}))
+
+		c, err := graph.Diff(a, b)
+		require.NoError(t, err)
+		assert.False(t, c.HasChanges())
+		assert.Len(t, c.Graph.Nodes, 2)
+		assert.Len(t, c.Graph.Edges, 1)
+	})
+}
@@ -0,0 +1,22 @@
+package graph
+
+// Direction is the direction in which
+// the Edges are followed from a Node
+type Direction int
+
+//go:generate ./../bin/enumer -type=Direction -transform=lower -trimprefix=Direction -output=direction_string.go
+
+// List of all Directions
+const (
+	// DirectionOut follows the Edges in which
+	// the Node is the Source, to the Target
+	DirectionOut Direction = iota
+
+	// DirectionIn follows the Edges in which
+	// the Node is the Target, to the Source
+	DirectionIn
+
+	// DirectionBoth follows all the
+	// Edges ignoring the direction
+	DirectionBoth
+)
@@ -16,6 +16,10 @@ type Edge struct {
 
 	Source string
 	Target string
+
+	// Action is the change of the
+	// Edge, if generated from a Diff
+	Action Action
 }
 
 // Replace replaces the src (on Target or Source) for rep values
@@ -8,8 +8,9 @@ import (
 
 // Graph defines the standard format of a Graph
 type Graph struct {
-	Edges []*Edge
-	Nodes []*Node
+	Edges  []*Edge
+	Nodes  []*Node
+	Groups []*Group
 
 	// nodesCans canonical -> struct{}{}
 	nodesCans map[string]*Node
@@ -26,6 +27,9 @@ type Graph struct {
 
 	// edgesIDs id -> struct{}{}
 	edgesIDs map[string]*Edge
+
+	// groupsIDs id -> *Group
+	groupsIDs map[string]*Group
 }
 
 // New returns a new initialized Graph
@@ -35,6 +39,7 @@ func New() *Graph {
 		nodesIDs:          make(map[string]*Node),
 		edgesSourceTarget: make(map[string]*Edge),
 		edgesIDs:          make(map[string]*Edge),
+		groupsIDs:         make(map[string]*Group),
 
 		nodesWithEdge: make(map[string][]*Edge),
 	}
@@ -123,6 +128,73 @@ func (g *Graph) GetNodeByCanonical(nCan string) (*Node, error) {
 	return n, nil
 }
 
+// AddGroup adds a Group to the Graph, the
+// Parent of it has to be already on the Graph
+func (g *Graph) AddGroup(gr *Group) error {
+	if gr.Canonical == "" {
+		return errcode.ErrGraphRequiredGroupCanonical
+	}
+	if gr.ID == "" {
+		return errcode.ErrGraphRequiredGroupID
+	}
+
+	if _, ok := g.groupsIDs[gr.ID]; ok {
+		return errcode.ErrGraphAlreadyExistsGroupID
+	}
+
+	if gr.Parent != "" {
+		if _, ok := g.groupsIDs[gr.Parent]; !ok {
+			return fmt.Errorf("parent %q: %w", gr.Parent, errcode.ErrGraphNotFoundGroup)
+		}
+	}
+
+	g.groupsIDs[gr.ID] = gr
+
+	g.Groups = append(g.Groups, gr)
+
+	return nil
+}
+
+// GetGroupByID returns the requested Group with the gID
+func (g *Graph) GetGroupByID(gID string) (*Group, error) {
+	gr, ok := g.groupsIDs[gID]
+	if !ok {
+		return nil, errcode.ErrGraphNotFoundGroup
+	}
+	return gr, nil
+}
+
+// CleanGroups removes all the Groups that do not
+// have any Node, directly or on any of the
+// Groups they contain
+func (g *Graph) CleanGroups() {
+	used := make(map[string]struct{})
+	for _, n := range g.Nodes {
+		for gID := n.Group; gID != ""; {
+			if _, ok := used[gID]; ok {
+				break
+			}
+			used[gID] = struct{}{}
+
+			gr, ok := g.groupsIDs[gID]
+			if !ok {
+				break
+			}
+			gID = gr.Parent
+		}
+	}
+
+	groups := make([]*Group, 0, len(used))
+	for _, gr := range g.Groups {
+		if _, ok := used[gr.ID]; !ok {
+			delete(g.groupsIDs, gr.ID)
+			continue
+		}
+		groups = append(groups, gr)
+	}
+	g.Groups = groups
+}
+
 // Clean removes all the Nodes that do not
 // have any edge
 func (g *Graph) Clean() {
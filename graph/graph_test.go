@@ -1,6 +1,7 @@
 package graph_test
 
 import (
+	"errors"
 	"sort"
 	"testing"
 
@@ -361,6 +362,83 @@ func TestClean(t *testing.T) {
 	})
 }
 
+func TestAddGroup(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "can1"}
+		gr2 := &graph.Group{ID: "2", Canonical: "can2", Parent: "1"}
+
+		err := g.AddGroup(gr1)
+		require.NoError(t, err)
+
+		err = g.AddGroup(gr2)
+		require.NoError(t, err)
+
+		assert.Equal(t, []*graph.Group{gr1, gr2}, g.Groups)
+
+		gr, err := g.GetGroupByID("2")
+		require.NoError(t, err)
+		assert.Equal(t, gr2, gr)
+	})
+	t.Run("RequiredGroupCanonical", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1"}
+
+		err := g.AddGroup(gr1)
+		assert.True(t, errors.Is(err, errcode.ErrGraphRequiredGroupCanonical))
+	})
+	t.Run("RequiredGroupID", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{Canonical: "can1"}
+
+		err := g.AddGroup(gr1)
+		assert.True(t, errors.Is(err, errcode.ErrGraphRequiredGroupID))
+	})
+	t.Run("AlreadyExistsGroupID", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "can1"}
+		gr2 := &graph.Group{ID: "1", Canonical: "can2"}
+
+		err := g.AddGroup(gr1)
+		require.NoError(t, err)
+
+		err = g.AddGroup(gr2)
+		assert.True(t, errors.Is(err, errcode.ErrGraphAlreadyExistsGroupID))
+	})
+	t.Run("NotFoundGroupParent", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "can1", Parent: "2"}
+
+		err := g.AddGroup(gr1)
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundGroup))
+	})
+}
+
+func TestCleanGroups(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "vpc"}
+		gr2 := &graph.Group{ID: "2", Canonical: "subnet1", Parent: "1"}
+		gr3 := &graph.Group{ID: "3", Canonical: "subnet2", Parent: "1"}
+		gr4 := &graph.Group{ID: "4", Canonical: "vpc2"}
+		n1 := &graph.Node{ID: "1", Canonical: "can1", Group: "2"}
+		n2 := &graph.Node{ID: "2", Canonical: "can2"}
+
+		for _, gr := range []*graph.Group{gr1, gr2, gr3, gr4} {
+			require.NoError(t, g.AddGroup(gr))
+		}
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+
+		g.CleanGroups()
+
+		assert.Equal(t, []*graph.Group{gr1, gr2}, g.Groups)
+
+		_, err := g.GetGroupByID("3")
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundGroup))
+	})
+}
+
 func TestNodeReplace(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		g := graph.New()
@@ -0,0 +1,27 @@
+package graph
+
+import "github.com/cycloidio/tfdocs/resource"
+
+// Group defines a container of Nodes, like
+// a VPC or a Subnet
+type Group struct {
+	// ID it'a a random UUID
+	ID string
+
+	// Canonical it's 'aws_vpc.main' format
+	Canonical string
+
+	// Name it's the name of the resource
+	Name string
+
+	// TFID it's the internal ID it has on TF
+	TFID string
+
+	// Resource it's the information of the resource
+	// it holds
+	Resource resource.Resource
+
+	// Parent is the ID of the Group that contains
+	// this one, empty if it has none
+	Parent string
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MatchCanonical checks if the can matches the pattern, in which the
+// '*' matches any characters, including '.' and '/', and the '?' only
+// one. The Canonicals inside of the pattern also match, so 'im_out'
+// matches 'im_out.tcp/80->80' and 'module.front' all the resources
+// of the module, as the rest of the Canonical starts with '.'. The
+// pattern also matches the resources inside of any module, so
+// 'aws_db_instance.*' matches 'module.rds["a"].aws_db_instance.db'
+func MatchCanonical(pattern, can string) bool {
+	return canonicalRegexp(pattern).MatchString(can)
+}
+
+// reModulePrefix matches the path of the modules before the resource,
+// like 'module.a.module.b["c.d"].', the keys are quoted or numbers
+const reModulePrefix = `(?:module\.[^.\[]+(?:\[(?:[0-9]+|"(?:[^"\\]|\\.)*")\])?\.)*`
+
+// canonicalRegexp converts the pattern of
+// MatchCanonical to the equivalent regexp
+func canonicalRegexp(pattern string) *regexp.Regexp {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.ReplaceAll(re, `\*`, `.*`)
+	re = strings.ReplaceAll(re, `\?`, `.`)
+	return regexp.MustCompile(`^` + reModulePrefix + `(?:` + re + `)(?:\..*)?$`)
+}
+
+// FindNodes returns the Nodes which Canonical matches
+// the pattern, as MatchCanonical, sorted by Canonical
+func (g *Graph) FindNodes(pattern string) []*Node {
+	re := canonicalRegexp(pattern)
+
+	res := make([]*Node, 0)
+	for _, n := range g.Nodes {
+		if re.MatchString(n.Canonical) {
+			res = append(res, n)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Canonical < res[j].Canonical })
+
+	return res
+}
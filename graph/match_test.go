@@ -0,0 +1,49 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+)
+
+func TestMatchCanonical(t *testing.T) {
+	tests := []struct {
+		Pattern   string
+		Canonical string
+		Match     bool
+	}{
+		{Pattern: "aws_lb.front", Canonical: "aws_lb.front", Match: true},
+		{Pattern: "aws_lb.front", Canonical: "aws_lb.frontend", Match: false},
+		{Pattern: "im_out", Canonical: "im_out.tcp/80->80", Match: true},
+		{Pattern: "aws_db_instance.*", Canonical: "aws_db_instance.db", Match: true},
+		{Pattern: "aws_db_instance.*", Canonical: "module.rds.aws_db_instance.db", Match: true},
+		{Pattern: "aws_db_instance.*", Canonical: `module.a.module.rds["a.b"].aws_db_instance.db`, Match: true},
+		{Pattern: "aws_db_instance.*", Canonical: "module.rds.aws_instance.db", Match: false},
+		{Pattern: "aws_db_instance.*", Canonical: "network.aws_db_instance.db", Match: false},
+		{Pattern: "module.rds", Canonical: "module.a.module.rds.aws_db_instance.db", Match: true},
+		{Pattern: "*aws_db_instance.*", Canonical: "module.rds.aws_db_instance.db", Match: true},
+		{Pattern: "module.rds", Canonical: "module.rds.aws_db_instance.db", Match: true},
+		{Pattern: "module.rds", Canonical: "module.rds2.aws_db_instance.db", Match: false},
+		{Pattern: "aws_instance.web?", Canonical: "aws_instance.web1", Match: true},
+		{Pattern: "aws_instance.web[0]", Canonical: "aws_instance.web[0]", Match: true},
+		{Pattern: "aws_instance.web[0]", Canonical: "aws_instance.web0", Match: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Match, graph.MatchCanonical(tt.Pattern, tt.Canonical), "%s %s", tt.Pattern, tt.Canonical)
+	}
+}
+
+func TestFindNodes(t *testing.T) {
+	g := newQueryGraph(t)
+
+	assert.Equal(t, []string{"aws_instance.api", "aws_instance.bastion", "aws_instance.web"}, canonicals(g.FindNodes("aws_instance")))
+	assert.Equal(t, []string{"im_out.tcp/80->80"}, canonicals(g.FindNodes("im_out")))
+	assert.Empty(t, g.FindNodes("aws_rds_cluster"))
+
+	require.NoError(t, g.AddNode(&graph.Node{ID: "module", Canonical: "module.rds.aws_db_instance.main"}))
+	assert.Equal(t, []string{"aws_db_instance.db", "module.rds.aws_db_instance.main"}, canonicals(g.FindNodes("aws_db_instance.*")))
+}
@@ -1,6 +1,10 @@
 package graph
 
-import "github.com/cycloidio/tfdocs/resource"
+import (
+	"strings"
+
+	"github.com/cycloidio/tfdocs/resource"
+)
 
 // Node defines the standard format of an Edge
 type Node struct {
@@ -26,4 +30,66 @@ type Node struct {
 	// Weight is the addition of the Directions
 	// of the Node
 	Weight int
+
+	// Group is the ID of the Group that
+	// contains the Node, if any
+	Group string
+
+	// Instances is the number of instances of the
+	// resource that the Node represents, it's only
+	// set when they are collapsed into one Node
+	Instances int
+
+	// Action is the change planned for the
+	// resource, if generated from a plan
+	Action Action
+}
+
+// Module returns the module path of the Node taken from the
+// Canonical, so for 'module.a.module.b.aws_lb.front' it'll
+// return 'module.a.module.b' and an empty string if the
+// Node is not inside a module
+func (n *Node) Module() string {
+	keys := SplitCanonical(n.Canonical)
+
+	i := 0
+	for i+1 < len(keys) && keys[i] == "module" {
+		i += 2
+	}
+
+	return strings.Join(keys[:i], ".")
+}
+
+// SplitCanonical splits the can on each '.' that is not inside
+// the key of an instance, so 'module.a["b.c"].aws_lb.front'
+// returns 'module', 'a["b.c"]', 'aws_lb' and 'front'
+func SplitCanonical(can string) []string {
+	res := make([]string, 0)
+
+	var (
+		start    int
+		inKey    bool
+		inQuote  bool
+		escaping bool
+	)
+	for i, r := range can {
+		switch {
+		case escaping:
+			escaping = false
+		case inQuote && r == '\\':
+			escaping = true
+		case inKey && r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inKey = true
+		case r == ']':
+			inKey = false
+		case !inKey && r == '.':
+			res = append(res, can[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(res, can[start:])
 }
@@ -0,0 +1,55 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cycloidio/inframap/graph"
+)
+
+func TestNodeModule(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Canonical string
+		EModule   string
+	}{
+		{
+			Name:      "SuccessNoModule",
+			Canonical: "aws_lb.front",
+			EModule:   "",
+		},
+		{
+			Name:      "SuccessModule",
+			Canonical: "module.a.aws_lb.front",
+			EModule:   "module.a",
+		},
+		{
+			Name:      "SuccessNestedModule",
+			Canonical: "module.a.module.b.aws_lb.front",
+			EModule:   "module.a.module.b",
+		},
+		{
+			Name:      "SuccessModuleKey",
+			Canonical: `module.a["b.c"].module.d[0].aws_lb.front`,
+			EModule:   `module.a["b.c"].module.d[0]`,
+		},
+		{
+			Name:      "SuccessModuleKeyEscaped",
+			Canonical: `module.a["b\".]c"].aws_lb.front`,
+			EModule:   `module.a["b\".]c"]`,
+		},
+		{
+			Name:      "SuccessResourceNamedModule",
+			Canonical: "module.a.aws_lb.module",
+			EModule:   "module.a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			n := graph.Node{Canonical: tt.Canonical}
+			assert.Equal(t, tt.EModule, n.Module())
+		})
+	}
+}
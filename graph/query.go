@@ -0,0 +1,315 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+)
+
+// Path is a list of Nodes connected by Edges,
+// the Edges[i] connects the Nodes[i] and Nodes[i+1]
+type Path struct {
+	Nodes []*Node
+	Edges []*Edge
+}
+
+// String returns the Canonicals of the Nodes
+// of the p like 'aws_lb.front -> aws_instance.web'
+func (p Path) String() string {
+	cans := make([]string, 0, len(p.Nodes))
+	for _, n := range p.Nodes {
+		cans = append(cans, n.Canonical)
+	}
+	return strings.Join(cans, " -> ")
+}
+
+// adjacent is an Edge of a Node with
+// the Node on the other side of it
+type adjacent struct {
+	Edge *Edge
+	Node *Node
+}
+
+// adjacents returns the Edges of the Node with the nID on
+// the Direction d, sorted by the Canonical of the Node
+// on the other side so the traversals are stable
+func (g *Graph) adjacents(nID string, d Direction) []adjacent {
+	res := make([]adjacent, 0)
+	for _, e := range g.nodesWithEdge[nID] {
+		var oID string
+		if e.Source == nID && (d == DirectionOut || d == DirectionBoth) {
+			oID = e.Target
+		} else if e.Target == nID && (d == DirectionIn || d == DirectionBoth) {
+			oID = e.Source
+		} else {
+			continue
+		}
+
+		n, ok := g.nodesIDs[oID]
+		if !ok {
+			continue
+		}
+		res = append(res, adjacent{Edge: e, Node: n})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Node.Canonical < res[j].Node.Canonical })
+
+	return res
+}
+
+// Neighbours returns the Nodes connected directly to the Node with
+// the Canonical can on the Direction d, sorted by Canonical
+func (g *Graph) Neighbours(can string, d Direction) ([]*Node, error) {
+	n, err := g.GetNodeByCanonical(can)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Node, 0)
+	visited := make(map[string]struct{})
+	for _, a := range g.adjacents(n.ID, d) {
+		if _, ok := visited[a.Node.ID]; ok {
+			continue
+		}
+		visited[a.Node.ID] = struct{}{}
+		res = append(res, a.Node)
+	}
+
+	return res, nil
+}
+
+// Reachable returns all the Nodes that can be reached from the Node
+// with the Canonical can following the Edges on the Direction d,
+// sorted by Canonical. With DirectionIn they are the Nodes that
+// can reach the Node, like all the ones that can reach a database
+func (g *Graph) Reachable(can string, d Direction) ([]*Node, error) {
+	n, err := g.GetNodeByCanonical(can)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Node, 0)
+	visited := map[string]struct{}{n.ID: struct{}{}}
+	queue := []*Node{n}
+	for len(queue) != 0 {
+		cn := queue[0]
+		queue = queue[1:]
+
+		for _, a := range g.adjacents(cn.ID, d) {
+			if _, ok := visited[a.Node.ID]; ok {
+				continue
+			}
+			visited[a.Node.ID] = struct{}{}
+			res = append(res, a.Node)
+			queue = append(queue, a.Node)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Canonical < res[j].Canonical })
+
+	return res, nil
+}
+
+// ShortestPath returns the Path with less Edges from the Node with the
+// Canonical src to the one with tgt following the direction of the
+// Edges, if there is none it returns errcode.ErrGraphNotFoundPath
+func (g *Graph) ShortestPath(src, tgt string) (Path, error) {
+	sn, err := g.GetNodeByCanonical(src)
+	if err != nil {
+		return Path{}, err
+	}
+
+	tn, err := g.GetNodeByCanonical(tgt)
+	if err != nil {
+		return Path{}, err
+	}
+
+	// prev holds the Node.ID -> adjacent
+	// from which the Node was reached
+	prev := map[string]adjacent{sn.ID: adjacent{}}
+	queue := []*Node{sn}
+	for len(queue) != 0 && queue[0].ID != tn.ID {
+		cn := queue[0]
+		queue = queue[1:]
+
+		for _, a := range g.adjacents(cn.ID, DirectionOut) {
+			if _, ok := prev[a.Node.ID]; ok {
+				continue
+			}
+			prev[a.Node.ID] = adjacent{Edge: a.Edge, Node: cn}
+			queue = append(queue, a.Node)
+		}
+	}
+
+	if _, ok := prev[tn.ID]; !ok {
+		return Path{}, errcode.ErrGraphNotFoundPath
+	}
+
+	p := Path{
+		Nodes: []*Node{tn},
+		Edges: make([]*Edge, 0),
+	}
+	for id := tn.ID; id != sn.ID; {
+		a := prev[id]
+		p.Nodes = append([]*Node{a.Node}, p.Nodes...)
+		p.Edges = append([]*Edge{a.Edge}, p.Edges...)
+		id = a.Node.ID
+	}
+
+	return p, nil
+}
+
+// AllPaths returns all the Paths, without repeating Nodes, from
+// the Node with the Canonical src to the one with tgt following
+// the direction of the Edges, sorted from the shortest one. As the
+// number of Paths can grow exponentially they can be limited: if the
+// maxEdges is positive the Paths have at most that number of Edges
+// and if the maxPaths is positive only that number of the shortest
+// Paths are returned
+func (g *Graph) AllPaths(src, tgt string, maxEdges, maxPaths int) ([]Path, error) {
+	sn, err := g.GetNodeByCanonical(src)
+	if err != nil {
+		return nil, err
+	}
+
+	tn, err := g.GetNodeByCanonical(tgt)
+	if err != nil {
+		return nil, err
+	}
+
+	// A Path without repeating Nodes
+	// can not have more Edges
+	if maxEdges <= 0 || maxEdges > len(g.Nodes)-1 {
+		maxEdges = len(g.Nodes) - 1
+	}
+
+	res := make([]Path, 0)
+	visited := make(map[string]struct{})
+
+	// The Paths are searched by the number of Edges, first the ones
+	// with 1, then with 2 ..., so when the maxPaths are found the
+	// search stops and the longer ones are not walked. The deeper
+	// is set when any walk reached the depth without the tgt, if
+	// not there are no longer Paths
+	var deeper bool
+	var walk func(n *Node, p Path, depth int)
+	walk = func(n *Node, p Path, depth int) {
+		if maxPaths > 0 && len(res) >= maxPaths {
+			return
+		}
+
+		if len(p.Edges) == depth {
+			if n.ID == tn.ID {
+				res = append(res, Path{
+					Nodes: append([]*Node{}, p.Nodes...),
+					Edges: append([]*Edge{}, p.Edges...),
+				})
+			} else {
+				deeper = true
+			}
+			return
+		}
+
+		if n.ID == tn.ID {
+			return
+		}
+
+		visited[n.ID] = struct{}{}
+		for _, a := range g.adjacents(n.ID, DirectionOut) {
+			if _, ok := visited[a.Node.ID]; ok {
+				continue
+			}
+			walk(a.Node, Path{
+				Nodes: append(p.Nodes, a.Node),
+				Edges: append(p.Edges, a.Edge),
+			}, depth)
+		}
+		delete(visited, n.ID)
+	}
+
+	for depth := 1; depth <= maxEdges; depth++ {
+		deeper = false
+		walk(sn, Path{Nodes: []*Node{sn}, Edges: make([]*Edge, 0)}, depth)
+		if !deeper || (maxPaths > 0 && len(res) >= maxPaths) {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// ConnectedComponents returns the groups of Nodes that are connected
+// between them, ignoring the direction of the Edges. The Nodes of each
+// one are sorted by Canonical and the components by their first Node
+func (g *Graph) ConnectedComponents() [][]*Node {
+	res := make([][]*Node, 0)
+	visited := make(map[string]struct{})
+	for _, n := range g.Nodes {
+		if _, ok := visited[n.ID]; ok {
+			continue
+		}
+		visited[n.ID] = struct{}{}
+
+		cc := []*Node{n}
+		for i := 0; i < len(cc); i++ {
+			for _, a := range g.adjacents(cc[i].ID, DirectionBoth) {
+				if _, ok := visited[a.Node.ID]; ok {
+					continue
+				}
+				visited[a.Node.ID] = struct{}{}
+				cc = append(cc, a.Node)
+			}
+		}
+
+		sort.Slice(cc, func(i, j int) bool { return cc[i].Canonical < cc[j].Canonical })
+		res = append(res, cc)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i][0].Canonical < res[j][0].Canonical })
+
+	return res
+}
+
+// TopologicalSort returns all the Nodes sorted so the Source of each Edge
+// is before the Target, when more than one Node can be next the one with
+// the lower Canonical goes first. If the Graph has a cycle it returns
+// errcode.ErrGraphCycle
+func (g *Graph) TopologicalSort() ([]*Node, error) {
+	// inDegree holds the Node.ID -> number of
+	// Edges to it from Nodes not yet sorted
+	inDegree := make(map[string]int, len(g.Nodes))
+	for _, n := range g.Nodes {
+		for _, a := range g.adjacents(n.ID, DirectionOut) {
+			inDegree[a.Node.ID]++
+		}
+	}
+
+	ready := make([]*Node, 0)
+	for _, n := range g.Nodes {
+		if inDegree[n.ID] == 0 {
+			ready = append(ready, n)
+		}
+	}
+
+	res := make([]*Node, 0, len(g.Nodes))
+	for len(ready) != 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i].Canonical < ready[j].Canonical })
+		n := ready[0]
+		ready = ready[1:]
+		res = append(res, n)
+
+		for _, a := range g.adjacents(n.ID, DirectionOut) {
+			inDegree[a.Node.ID]--
+			if inDegree[a.Node.ID] == 0 {
+				ready = append(ready, a.Node)
+			}
+		}
+	}
+
+	if len(res) != len(g.Nodes) {
+		return nil, errcode.ErrGraphCycle
+	}
+
+	return res, nil
+}
@@ -0,0 +1,169 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+)
+
+// newQueryGraph returns a Graph like:
+//
+//	im_out -> lb -> web -> db
+//	          lb -> api -> db
+//	bastion
+func newQueryGraph(t *testing.T) *graph.Graph {
+	g := graph.New()
+	for _, c := range []string{"im_out.tcp/80->80", "aws_lb.front", "aws_instance.web", "aws_instance.api", "aws_db_instance.db", "aws_instance.bastion"} {
+		require.NoError(t, g.AddNode(&graph.Node{ID: c, Canonical: c}))
+	}
+	for i, st := range [][2]string{
+		{"im_out.tcp/80->80", "aws_lb.front"},
+		{"aws_lb.front", "aws_instance.web"},
+		{"aws_lb.front", "aws_instance.api"},
+		{"aws_instance.web", "aws_db_instance.db"},
+		{"aws_instance.api", "aws_db_instance.db"},
+	} {
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: string(rune('a' + i)), Source: st[0], Target: st[1]}))
+	}
+	return g
+}
+
+func canonicals(nodes []*graph.Node) []string {
+	res := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, n.Canonical)
+	}
+	return res
+}
+
+func TestNeighbours(t *testing.T) {
+	g := newQueryGraph(t)
+
+	t.Run("SuccessOut", func(t *testing.T) {
+		ns, err := g.Neighbours("aws_lb.front", graph.DirectionOut)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"aws_instance.api", "aws_instance.web"}, canonicals(ns))
+	})
+	t.Run("SuccessIn", func(t *testing.T) {
+		ns, err := g.Neighbours("aws_lb.front", graph.DirectionIn)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"im_out.tcp/80->80"}, canonicals(ns))
+	})
+	t.Run("SuccessBoth", func(t *testing.T) {
+		ns, err := g.Neighbours("aws_lb.front", graph.DirectionBoth)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"aws_instance.api", "aws_instance.web", "im_out.tcp/80->80"}, canonicals(ns))
+	})
+	t.Run("ErrGraphNotFoundNode", func(t *testing.T) {
+		_, err := g.Neighbours("aws_lb.back", graph.DirectionOut)
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundNode))
+	})
+}
+
+func TestReachable(t *testing.T) {
+	g := newQueryGraph(t)
+
+	t.Run("SuccessOut", func(t *testing.T) {
+		ns, err := g.Reachable("aws_lb.front", graph.DirectionOut)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"aws_db_instance.db", "aws_instance.api", "aws_instance.web"}, canonicals(ns))
+	})
+	t.Run("SuccessIn", func(t *testing.T) {
+		ns, err := g.Reachable("aws_db_instance.db", graph.DirectionIn)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"aws_instance.api", "aws_instance.web", "aws_lb.front", "im_out.tcp/80->80"}, canonicals(ns))
+	})
+	t.Run("SuccessNone", func(t *testing.T) {
+		ns, err := g.Reachable("aws_instance.bastion", graph.DirectionBoth)
+		require.NoError(t, err)
+		assert.Empty(t, ns)
+	})
+}
+
+func TestShortestPath(t *testing.T) {
+	g := newQueryGraph(t)
+
+	t.Run("Success", func(t *testing.T) {
+		p, err := g.ShortestPath("im_out.tcp/80->80", "aws_db_instance.db")
+		require.NoError(t, err)
+		assert.Equal(t, "im_out.tcp/80->80 -> aws_lb.front -> aws_instance.api -> aws_db_instance.db", p.String())
+		require.Len(t, p.Edges, 3)
+		assert.Equal(t, "aws_lb.front", p.Edges[1].Source)
+		assert.Equal(t, "aws_instance.api", p.Edges[1].Target)
+	})
+	t.Run("SuccessSameNode", func(t *testing.T) {
+		p, err := g.ShortestPath("aws_lb.front", "aws_lb.front")
+		require.NoError(t, err)
+		assert.Equal(t, "aws_lb.front", p.String())
+		assert.Empty(t, p.Edges)
+	})
+	t.Run("ErrGraphNotFoundPath", func(t *testing.T) {
+		_, err := g.ShortestPath("aws_db_instance.db", "aws_lb.front")
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundPath))
+	})
+}
+
+func TestAllPaths(t *testing.T) {
+	g := newQueryGraph(t)
+
+	t.Run("Success", func(t *testing.T) {
+		ps, err := g.AllPaths("im_out.tcp/80->80", "aws_db_instance.db", 0, 0)
+		require.NoError(t, err)
+		require.Len(t, ps, 2)
+		assert.Equal(t, "im_out.tcp/80->80 -> aws_lb.front -> aws_instance.api -> aws_db_instance.db", ps[0].String())
+		assert.Equal(t, "im_out.tcp/80->80 -> aws_lb.front -> aws_instance.web -> aws_db_instance.db", ps[1].String())
+		assert.Len(t, ps[1].Edges, 3)
+	})
+	t.Run("SuccessMaxPaths", func(t *testing.T) {
+		ps, err := g.AllPaths("im_out.tcp/80->80", "aws_db_instance.db", 0, 1)
+		require.NoError(t, err)
+		require.Len(t, ps, 1)
+		assert.Equal(t, "im_out.tcp/80->80 -> aws_lb.front -> aws_instance.api -> aws_db_instance.db", ps[0].String())
+	})
+	t.Run("SuccessMaxEdges", func(t *testing.T) {
+		ps, err := g.AllPaths("im_out.tcp/80->80", "aws_db_instance.db", 2, 0)
+		require.NoError(t, err)
+		assert.Empty(t, ps)
+
+		ps, err = g.AllPaths("im_out.tcp/80->80", "aws_instance.web", 2, 0)
+		require.NoError(t, err)
+		require.Len(t, ps, 1)
+		assert.Equal(t, "im_out.tcp/80->80 -> aws_lb.front -> aws_instance.web", ps[0].String())
+	})
+	t.Run("SuccessNone", func(t *testing.T) {
+		ps, err := g.AllPaths("aws_instance.bastion", "aws_db_instance.db", 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, ps)
+	})
+}
+
+func TestConnectedComponents(t *testing.T) {
+	g := newQueryGraph(t)
+
+	ccs := g.ConnectedComponents()
+	require.Len(t, ccs, 2)
+	assert.Equal(t, []string{"aws_db_instance.db", "aws_instance.api", "aws_instance.web", "aws_lb.front", "im_out.tcp/80->80"}, canonicals(ccs[0]))
+	assert.Equal(t, []string{"aws_instance.bastion"}, canonicals(ccs[1]))
+}
+
+func TestTopologicalSort(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := newQueryGraph(t)
+
+		ns, err := g.TopologicalSort()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"aws_instance.bastion", "im_out.tcp/80->80", "aws_lb.front", "aws_instance.api", "aws_instance.web", "aws_db_instance.db"}, canonicals(ns))
+	})
+	t.Run("ErrGraphCycle", func(t *testing.T) {
+		g := newQueryGraph(t)
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "cycle", Source: "aws_db_instance.db", Target: "aws_lb.front"}))
+
+		_, err := g.TopologicalSort()
+		assert.True(t, errors.Is(err, errcode.ErrGraphCycle))
+	})
+}
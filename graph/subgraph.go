@@ -0,0 +1,97 @@
+package graph
+
+import "fmt"
+
+// Subgraph returns a new Graph with the Nodes at most depth Edges away,
+// in any direction, from the Nodes with the cans Canonicals, the Edges
+// between them and the Groups that contain them. If the depth is negative
+// there is no limit. The Nodes, Edges and Groups are shared with the g
+func (g *Graph) Subgraph(cans []string, depth int) (*Graph, error) {
+	// dist holds the Node.ID -> number of Edges
+	// from the closest of the cans
+	dist := make(map[string]int)
+	queue := make([]*Node, 0, len(cans))
+	for _, c := range cans {
+		n, err := g.GetNodeByCanonical(c)
+		if err != nil {
+			return nil, fmt.Errorf("with canonical %q: %w", c, err)
+		}
+		if _, ok := dist[n.ID]; ok {
+			continue
+		}
+		dist[n.ID] = 0
+		queue = append(queue, n)
+	}
+
+	for len(queue) != 0 {
+		cn := queue[0]
+		queue = queue[1:]
+
+		if depth >= 0 && dist[cn.ID] >= depth {
+			continue
+		}
+
+		for _, a := range g.adjacents(cn.ID, DirectionBoth) {
+			if _, ok := dist[a.Node.ID]; ok {
+				continue
+			}
+			dist[a.Node.ID] = dist[cn.ID] + 1
+			queue = append(queue, a.Node)
+		}
+	}
+
+	sg := New()
+
+	// The Groups are added with the Parents always before
+	// them, as they may not be before on the g.Groups, and
+	// then the ones without any of the Nodes are removed
+	added := make(map[string]struct{})
+	var addGroup func(gr *Group) error
+	addGroup = func(gr *Group) error {
+		if _, ok := added[gr.ID]; ok {
+			return nil
+		}
+		added[gr.ID] = struct{}{}
+
+		if p, ok := g.groupsIDs[gr.Parent]; ok && gr.Parent != "" {
+			err := addGroup(p)
+			if err != nil {
+				return err
+			}
+		}
+
+		return sg.AddGroup(gr)
+	}
+	for _, gr := range g.Groups {
+		err := addGroup(gr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, ok := dist[n.ID]; !ok {
+			continue
+		}
+		err := sg.AddNode(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range g.Edges {
+		_, sok := dist[e.Source]
+		_, tok := dist[e.Target]
+		if !sok || !tok {
+			continue
+		}
+		err := sg.AddEdge(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sg.CleanGroups()
+
+	return sg, nil
+}
@@ -0,0 +1,88 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+)
+
+func TestSubgraph(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := newQueryGraph(t)
+
+		sg, err := g.Subgraph([]string{"aws_instance.web"}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"aws_lb.front", "aws_instance.web", "aws_db_instance.db"}, canonicals(sg.Nodes))
+		require.Len(t, sg.Edges, 2)
+		assert.Equal(t, "aws_lb.front", sg.Edges[0].Source)
+		assert.Equal(t, "aws_instance.web", sg.Edges[0].Target)
+		assert.Equal(t, "aws_instance.web", sg.Edges[1].Source)
+		assert.Equal(t, "aws_db_instance.db", sg.Edges[1].Target)
+
+		// The original is not modified
+		assert.Len(t, g.Nodes, 6)
+		assert.Len(t, g.Edges, 5)
+	})
+	t.Run("SuccessDepth", func(t *testing.T) {
+		g := newQueryGraph(t)
+
+		sg, err := g.Subgraph([]string{"aws_instance.web"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"aws_instance.web"}, canonicals(sg.Nodes))
+		assert.Empty(t, sg.Edges)
+
+		sg, err = g.Subgraph([]string{"aws_instance.web"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"im_out.tcp/80->80", "aws_lb.front", "aws_instance.web", "aws_instance.api", "aws_db_instance.db"}, canonicals(sg.Nodes))
+		assert.Len(t, sg.Edges, 5)
+
+		sg, err = g.Subgraph([]string{"aws_instance.bastion", "im_out.tcp/80->80"}, -1)
+		require.NoError(t, err)
+		assert.Len(t, sg.Nodes, 6)
+	})
+	t.Run("SuccessGroups", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "aws_vpc.main"}
+		gr2 := &graph.Group{ID: "2", Canonical: "aws_subnet.front", Parent: "1"}
+		gr3 := &graph.Group{ID: "3", Canonical: "aws_subnet.back", Parent: "1"}
+		for _, gr := range []*graph.Group{gr1, gr2, gr3} {
+			require.NoError(t, g.AddGroup(gr))
+		}
+		require.NoError(t, g.AddNode(&graph.Node{ID: "1", Canonical: "aws_lb.front", Group: "2"}))
+		require.NoError(t, g.AddNode(&graph.Node{ID: "2", Canonical: "aws_instance.web", Group: "3"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+
+		sg, err := g.Subgraph([]string{"aws_lb.front"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []*graph.Group{gr1, gr2}, sg.Groups)
+		assert.Len(t, g.Groups, 3)
+	})
+	t.Run("SuccessGroupsChildBeforeParent", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "aws_subnet.front"}
+		gr2 := &graph.Group{ID: "2", Canonical: "aws_vpc.main"}
+		for _, gr := range []*graph.Group{gr1, gr2} {
+			require.NoError(t, g.AddGroup(gr))
+		}
+		// The Parent is set after adding
+		// them, as it's done when generating
+		gr1.Parent = "2"
+		require.NoError(t, g.AddNode(&graph.Node{ID: "1", Canonical: "aws_lb.front", Group: "1"}))
+
+		sg, err := g.Subgraph([]string{"aws_lb.front"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []*graph.Group{gr2, gr1}, sg.Groups)
+		assert.Equal(t, []*graph.Group{gr1, gr2}, g.Groups)
+	})
+	t.Run("ErrGraphNotFoundNode", func(t *testing.T) {
+		g := newQueryGraph(t)
+
+		_, err := g.Subgraph([]string{"aws_lb.back"}, 1)
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundNode))
+	})
+}
@@ -0,0 +1,158 @@
+package ascii
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	branch     = "|-- "
+	lastBranch = "`-- "
+	indent     = "|   "
+	lastIndent = "    "
+
+	// repeated is the mark of the Nodes that
+	// have already been printed with all its
+	// connections
+	repeated = " (*)"
+
+	reset = "\x1b[0m"
+)
+
+// colors are the ANSI colors used for each provider.Type,
+// the ones not present are not colored
+var colors = map[provider.Type]string{
+	provider.IM:             "\x1b[32m",
+	provider.AWS:            "\x1b[33m",
+	provider.FlexibleEngine: "\x1b[35m",
+	provider.OpenStack:      "\x1b[31m",
+	provider.Google:         "\x1b[34m",
+	provider.Azurerm:        "\x1b[36m",
+}
+
+// ASCII is the struct that implements the Printer
+// of an indented tree to be read on a terminal
+type ASCII struct{}
+
+// Print prints into w the g as an indented tree in which each
+// Node has as children the Nodes it connects to. The Nodes
+// with no incoming Edges are the roots of the tree
+func (a ASCII) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	nodes := make(map[string]*graph.Node)
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+
+	// outs holds graph.Node.ID -> []*graph.Edge
+	outs := make(map[string][]*graph.Edge)
+	ins := make(map[string]int)
+	for _, e := range g.Edges {
+		if _, ok := nodes[e.Source]; !ok {
+			return fmt.Errorf("edge source %q: %w", e.Source, errcode.ErrGraphNotFoundNode)
+		}
+		if _, ok := nodes[e.Target]; !ok {
+			return fmt.Errorf("edge target %q: %w", e.Target, errcode.ErrGraphNotFoundNode)
+		}
+		outs[e.Source] = append(outs[e.Source], e)
+		ins[e.Target]++
+	}
+	for _, es := range outs {
+		sort.Slice(es, func(i, j int) bool { return nodes[es[i].Target].Canonical < nodes[es[j].Target].Canonical })
+	}
+
+	label := func(n *graph.Node) string {
+		l := printer.NodeLabel(n, opt)
+		if !opt.Color {
+			return l
+		}
+
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+		c, ok := colors[pv.Type()]
+		if !ok {
+			return l
+		}
+		return c + l + reset
+	}
+
+	// printed holds the Nodes that have already been
+	// printed so they are not expanded again, which
+	// also prevents the loops on cyclic graphs
+	printed := make(map[string]struct{})
+	var hasRepeated bool
+
+	var walk func(nid, prefix string)
+	walk = func(nid, prefix string) {
+		for i, e := range outs[nid] {
+			br, ind := branch, indent
+			if i == len(outs[nid])-1 {
+				br, ind = lastBranch, lastIndent
+			}
+
+			t := nodes[e.Target]
+			line := prefix + br + label(t)
+			if len(e.Canonicals) != 0 {
+				line += fmt.Sprintf(" via %s", strings.Join(e.Canonicals, ", "))
+			}
+
+			_, ok := printed[t.ID]
+			if ok && len(outs[t.ID]) != 0 {
+				hasRepeated = true
+				line += repeated
+			}
+			fmt.Fprintln(bw, line)
+
+			if !ok {
+				printed[t.ID] = struct{}{}
+				walk(t.ID, prefix+ind)
+			}
+		}
+	}
+
+	sorted := printer.SortNodes(g.Nodes)
+
+	// The roots are the ones with no incoming Edges, and then
+	// the ones that are part of a cycle and have not been
+	// reached from any root
+	roots := make([]*graph.Node, 0)
+	for _, n := range sorted {
+		if ins[n.ID] == 0 {
+			roots = append(roots, n)
+		}
+	}
+
+	printRoot := func(n *graph.Node) {
+		printed[n.ID] = struct{}{}
+		fmt.Fprintln(bw, label(n))
+		walk(n.ID, "")
+	}
+
+	for _, n := range roots {
+		printRoot(n)
+	}
+	for _, n := range sorted {
+		if _, ok := printed[n.ID]; !ok {
+			printRoot(n)
+		}
+	}
+
+	if hasRepeated {
+		fmt.Fprintln(bw)
+		fmt.Fprintln(bw, "(*) already printed above with all its connections")
+	}
+
+	return bw.Flush()
+}
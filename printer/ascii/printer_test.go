@@ -0,0 +1,64 @@
+package ascii_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/ascii"
+)
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/80->80"}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_lb.front", Name: "front"}
+	n3 := &graph.Node{ID: "3", Canonical: "aws_instance.a"}
+	n4 := &graph.Node{ID: "4", Canonical: "aws_instance.b"}
+	n5 := &graph.Node{ID: "5", Canonical: "aws_db_instance.db"}
+	n6 := &graph.Node{ID: "6", Canonical: "aws_instance.c"}
+	n7 := &graph.Node{ID: "7", Canonical: "aws_instance.d"}
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+	e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n4.ID, Canonicals: []string{"aws_security_group.lb", "aws_security_group.b"}}
+	e3 := &graph.Edge{ID: "3", Source: n2.ID, Target: n3.ID}
+	e4 := &graph.Edge{ID: "4", Source: n3.ID, Target: n5.ID}
+	e5 := &graph.Edge{ID: "5", Source: n4.ID, Target: n5.ID}
+	e6 := &graph.Edge{ID: "6", Source: n6.ID, Target: n7.ID}
+	e7 := &graph.Edge{ID: "7", Source: n7.ID, Target: n6.ID}
+
+	for _, n := range []*graph.Node{n1, n2, n3, n4, n5, n6, n7} {
+		require.NoError(t, g.AddNode(n))
+	}
+	for _, e := range []*graph.Edge{e1, e2, e3, e4, e5, e6, e7} {
+		require.NoError(t, g.AddEdge(e))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var b bytes.Buffer
+		err := ascii.ASCII{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		assert.Equal(t, "im_out.tcp/80->80\n"+
+			"`-- aws_lb.front\n"+
+			"    |-- aws_instance.a\n"+
+			"    |   `-- aws_db_instance.db\n"+
+			"    `-- aws_instance.b via aws_security_group.lb, aws_security_group.b\n"+
+			"        `-- aws_db_instance.db\n"+
+			"aws_instance.c\n"+
+			"`-- aws_instance.d\n"+
+			"    `-- aws_instance.c (*)\n"+
+			"\n"+
+			"(*) already printed above with all its connections\n", b.String())
+	})
+	t.Run("SuccessColor", func(t *testing.T) {
+		var b bytes.Buffer
+		err := ascii.ASCII{}.Print(g, printer.Options{Color: true, AlternativeNodeNames: true}, &b)
+		require.NoError(t, err)
+
+		assert.Contains(t, b.String(), "\x1b[32mim_out.tcp/80->80\x1b[0m\n")
+		assert.Contains(t, b.String(), "`-- \x1b[33mfront\x1b[0m\n")
+	})
+}
@@ -6,14 +6,11 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/adrg/xdg"
 	"github.com/awalterschulze/gographviz"
-	"github.com/markbates/pkger"
 
-	// As we require to load the assets to be used
-	// we import it as empty
-	_ "github.com/cycloidio/inframap/assets"
 	"github.com/cycloidio/inframap/graph"
 	"github.com/cycloidio/inframap/printer"
 	"github.com/cycloidio/inframap/provider"
@@ -32,6 +29,18 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 	graph.SetDir(true)
 	graph.SetStrict(true)
 
+	// clusters holds the module path -> subgraph name
+	// of the already created clusters
+	clusters := make(map[string]string)
+
+	// groupClusters holds the graph.Group.ID -> subgraph name
+	// of the already created clusters
+	groupClusters := make(map[string]string)
+
+	// The clusters of the Groups are named from the Canonical,
+	// and not the ID, so the output is stable between executions
+	gids := printer.GroupIDs(g.Groups, nil)
+
 	for _, n := range g.Nodes {
 		// If it's nil the pv, it means we do not know it so we'll use
 		// the RawProvider.
@@ -49,10 +58,21 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 			attr["shape"] = "rectangle"
 		}
 
+		// The number of instances is only
+		// visible on the label of the Node
+		if n.Instances > 1 {
+			attr["label"] = fmt.Sprintf("%q", printer.NodeLabel(n, opt))
+		}
+
+		// The planned changes are displayed by
+		// coloring the Node and the label
+		if c := printer.ActionColor(n.Action, opt); c != "" {
+			attr["color"] = fmt.Sprintf("%q", c)
+			attr["fontcolor"] = fmt.Sprintf("%q", c)
+		}
+
 		if opt.ShowIcons && n.Resource.Icon != "" {
-			ext := path.Ext(n.Resource.Icon)
-			pngIcon := fmt.Sprintf("%s.png", n.Resource.Icon[0:len(n.Resource.Icon)-len(ext)])
-			assetPath := path.Join("inframap", "assets", pv.Type().String(), pngIcon)
+			assetPath := path.Join("inframap", "assets", printer.IconPath(pv.Type(), n.Resource.Icon))
 			pathIcon := path.Join(xdg.CacheHome, assetPath)
 
 			attr["image"] = fmt.Sprintf("%q", pathIcon)
@@ -74,7 +94,7 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 					return err
 				}
 
-				iconFile, err := pkger.Open(path.Join("/assets", "icons", pv.Type().String(), pngIcon))
+				iconFile, err := printer.OpenIcon(pv.Type(), n.Resource.Icon)
 				if err != nil {
 					return err
 				}
@@ -88,10 +108,25 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 			}
 		}
 
+		// The Groups take precedence over the modules
+		// as a Node can only be in one cluster
+		parent := parentName
+		if gr, err := g.GetGroupByID(n.Group); err == nil {
+			parent, err = addGroupCluster(graph, parentName, g, gr, opt, gids, groupClusters)
+			if err != nil {
+				return err
+			}
+		} else if opt.ClusterModules && n.Module() != "" {
+			parent, err = addModuleClusters(graph, parentName, n.Module(), clusters)
+			if err != nil {
+				return err
+			}
+		}
+
 		if opt.AlternativeNodeNames {
-			graph.AddNode(parentName, fmt.Sprintf("%q", n.Name), attr)
+			graph.AddNode(parent, fmt.Sprintf("%q", n.Name), attr)
 		} else {
-			graph.AddNode(parentName, fmt.Sprintf("%q", n.Canonical), attr)
+			graph.AddNode(parent, fmt.Sprintf("%q", n.Canonical), attr)
 		}
 	}
 
@@ -106,10 +141,17 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 			return err
 		}
 
+		var attr map[string]string
+		if c := printer.ActionColor(e.Action, opt); c != "" {
+			attr = map[string]string{
+				"color": fmt.Sprintf("%q", c),
+			}
+		}
+
 		if opt.AlternativeNodeNames {
-			graph.AddEdge(fmt.Sprintf("%q", src.Name), fmt.Sprintf("%q", tr.Name), true, nil)
+			graph.AddEdge(fmt.Sprintf("%q", src.Name), fmt.Sprintf("%q", tr.Name), true, attr)
 		} else {
-			graph.AddEdge(fmt.Sprintf("%q", src.Canonical), fmt.Sprintf("%q", tr.Canonical), true, nil)
+			graph.AddEdge(fmt.Sprintf("%q", src.Canonical), fmt.Sprintf("%q", tr.Canonical), true, attr)
 		}
 	}
 
@@ -118,3 +160,60 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 
 	return nil
 }
+
+// addModuleClusters adds, if they do not exist already, the nested
+// clusters for each level of the module path mp and returns the
+// name of the deepest one, in which the Node has to be added
+func addModuleClusters(gv *gographviz.Graph, parentName, mp string, clusters map[string]string) (string, error) {
+	keys := graph.SplitCanonical(mp)
+
+	parent := parentName
+	for i := 2; i <= len(keys); i += 2 {
+		p := strings.Join(keys[:i], ".")
+		name, ok := clusters[p]
+		if !ok {
+			// The subgraphs with the 'cluster' prefix
+			// are the ones drawn as a box by Graphviz
+			name = fmt.Sprintf("%q", "cluster_"+p)
+			err := gv.AddSubGraph(parent, name, map[string]string{
+				"label": fmt.Sprintf("%q", strings.Join(keys[i-2:i], ".")),
+			})
+			if err != nil {
+				return "", err
+			}
+			clusters[p] = name
+		}
+		parent = name
+	}
+
+	return parent, nil
+}
+
+// addGroupCluster adds, if it does not exist already, the cluster
+// of the gr and the ones of its Parents and returns the name
+// of the one of gr, in which the Node has to be added. The gids
+// are the ones from printer.GroupIDs used to name the clusters
+func addGroupCluster(gv *gographviz.Graph, parentName string, g *graph.Graph, gr *graph.Group, opt printer.Options, gids, clusters map[string]string) (string, error) {
+	if name, ok := clusters[gr.ID]; ok {
+		return name, nil
+	}
+
+	parent := parentName
+	if pgr, err := g.GetGroupByID(gr.Parent); err == nil {
+		parent, err = addGroupCluster(gv, parentName, g, pgr, opt, gids, clusters)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	name := fmt.Sprintf("%q", "cluster_"+gids[gr.ID])
+	err := gv.AddSubGraph(parent, name, map[string]string{
+		"label": fmt.Sprintf("%q", printer.GroupLabel(gr, opt)),
+	})
+	if err != nil {
+		return "", err
+	}
+	clusters[gr.ID] = name
+
+	return name, nil
+}
@@ -0,0 +1,194 @@
+package dot_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/awalterschulze/gographviz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/dot"
+)
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/80->80"}
+	n2 := &graph.Node{ID: "2", Canonical: "module.a.aws_lb.front"}
+	n3 := &graph.Node{ID: "3", Canonical: "module.a.module.b.aws_instance.front"}
+	n4 := &graph.Node{ID: "4", Canonical: "module.a.module.b.aws_db_instance.db"}
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+	e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+	e3 := &graph.Edge{ID: "3", Source: n3.ID, Target: n4.ID}
+
+	for _, n := range []*graph.Node{n1, n2, n3, n4} {
+		require.NoError(t, g.AddNode(n))
+	}
+	for _, e := range []*graph.Edge{e1, e2, e3} {
+		require.NoError(t, g.AddEdge(e))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var b bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		dg, err := gographviz.Read(b.Bytes())
+		require.NoError(t, err)
+
+		assert.Len(t, dg.Nodes.Nodes, 4)
+		assert.Len(t, dg.Edges.Edges, 3)
+		assert.Len(t, dg.SubGraphs.SubGraphs, 0)
+	})
+	t.Run("SuccessClusterModules", func(t *testing.T) {
+		var b bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{ClusterModules: true}, &b)
+		require.NoError(t, err)
+
+		dg, err := gographviz.Read(b.Bytes())
+		require.NoError(t, err)
+
+		assert.Len(t, dg.Nodes.Nodes, 4)
+		assert.Len(t, dg.Edges.Edges, 3)
+		require.Len(t, dg.SubGraphs.SubGraphs, 2)
+
+		ca := `"cluster_module.a"`
+		cb := `"cluster_module.a.module.b"`
+		assert.Equal(t, `"module.a"`, dg.SubGraphs.SubGraphs[ca].Attrs["label"])
+		assert.Equal(t, `"module.b"`, dg.SubGraphs.SubGraphs[cb].Attrs["label"])
+
+		assert.Contains(t, dg.Relations.ParentToChildren["G"], `"im_out.tcp/80->80"`)
+		assert.Contains(t, dg.Relations.ParentToChildren["G"], ca)
+		assert.Contains(t, dg.Relations.ParentToChildren[ca], `"module.a.aws_lb.front"`)
+		assert.Contains(t, dg.Relations.ParentToChildren[ca], cb)
+		assert.Contains(t, dg.Relations.ParentToChildren[cb], `"module.a.module.b.aws_instance.front"`)
+		assert.Contains(t, dg.Relations.ParentToChildren[cb], `"module.a.module.b.aws_db_instance.db"`)
+	})
+	t.Run("SuccessClusterModulesKey", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: `module.lemp["a.b"].aws_lb.front`}
+		n2 := &graph.Node{ID: "2", Canonical: `module.lemp["a.b"].aws_instance.web`}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e1))
+
+		var b bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{ClusterModules: true}, &b)
+		require.NoError(t, err)
+
+		dg, err := gographviz.Read(b.Bytes())
+		require.NoError(t, err)
+
+		require.Len(t, dg.SubGraphs.SubGraphs, 1)
+
+		c := `"cluster_module.lemp[\"a.b\"]"`
+		require.Contains(t, dg.SubGraphs.SubGraphs, c)
+		assert.Equal(t, `"module.lemp[\"a.b\"]"`, dg.SubGraphs.SubGraphs[c].Attrs["label"])
+		assert.Contains(t, dg.Relations.ParentToChildren[c], `"module.lemp[\"a.b\"].aws_lb.front"`)
+		assert.Contains(t, dg.Relations.ParentToChildren[c], `"module.lemp[\"a.b\"].aws_instance.web"`)
+	})
+	t.Run("SuccessGroups", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "aws_vpc.main", Name: "main"}
+		gr2 := &graph.Group{ID: "2", Canonical: "aws_subnet.public", Parent: gr1.ID}
+		n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/80->80"}
+		n2 := &graph.Node{ID: "2", Canonical: "module.a.aws_lb.front", Name: "front", Group: gr2.ID}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+
+		require.NoError(t, g.AddGroup(gr1))
+		require.NoError(t, g.AddGroup(gr2))
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e1))
+
+		var b bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{ClusterModules: true, AlternativeNodeNames: true}, &b)
+		require.NoError(t, err)
+
+		dg, err := gographviz.Read(b.Bytes())
+		require.NoError(t, err)
+
+		require.Len(t, dg.SubGraphs.SubGraphs, 2)
+
+		c1 := `"cluster_group_aws_vpc_main"`
+		c2 := `"cluster_group_aws_subnet_public"`
+		assert.Equal(t, `"main"`, dg.SubGraphs.SubGraphs[c1].Attrs["label"])
+		assert.Equal(t, `"aws_subnet.public"`, dg.SubGraphs.SubGraphs[c2].Attrs["label"])
+
+		assert.Contains(t, dg.Relations.ParentToChildren["G"], c1)
+		assert.Contains(t, dg.Relations.ParentToChildren[c1], c2)
+		assert.Contains(t, dg.Relations.ParentToChildren[c2], `"front"`)
+	})
+	t.Run("SuccessInstances", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front", Instances: 1}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.web", Instances: 3}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e1))
+
+		var b bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		dg, err := gographviz.Read(b.Bytes())
+		require.NoError(t, err)
+
+		assert.NotContains(t, dg.Nodes.Lookup[`"aws_lb.front"`].Attrs, gographviz.Label)
+		assert.Equal(t, `"aws_instance.web (x3)"`, dg.Nodes.Lookup[`"aws_instance.web"`].Attrs[gographviz.Label])
+	})
+	t.Run("SuccessActions", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.web", Action: graph.ActionCreate}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e1))
+
+		var b bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		dg, err := gographviz.Read(b.Bytes())
+		require.NoError(t, err)
+
+		assert.NotContains(t, dg.Nodes.Lookup[`"aws_lb.front"`].Attrs, gographviz.Color)
+		assert.Equal(t, `"#2e7d32"`, dg.Nodes.Lookup[`"aws_instance.web"`].Attrs[gographviz.Color])
+		assert.Equal(t, `"#2e7d32"`, dg.Nodes.Lookup[`"aws_instance.web"`].Attrs[gographviz.FontColor])
+	})
+	t.Run("SuccessDiff", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.web", Action: graph.ActionCreate}
+		n3 := &graph.Node{ID: "3", Canonical: "aws_instance.old", Action: graph.ActionDelete}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID, Action: graph.ActionCreate}
+		e2 := &graph.Edge{ID: "2", Source: n1.ID, Target: n3.ID, Action: graph.ActionDelete}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddNode(n3))
+		require.NoError(t, g.AddEdge(e1))
+		require.NoError(t, g.AddEdge(e2))
+
+		var b bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{Diff: true}, &b)
+		require.NoError(t, err)
+
+		dg, err := gographviz.Read(b.Bytes())
+		require.NoError(t, err)
+
+		assert.Equal(t, `"#9e9e9e"`, dg.Nodes.Lookup[`"aws_lb.front"`].Attrs[gographviz.Color])
+		assert.Equal(t, `"#2e7d32"`, dg.Nodes.Lookup[`"aws_instance.web"`].Attrs[gographviz.Color])
+		assert.Equal(t, `"#c62828"`, dg.Nodes.Lookup[`"aws_instance.old"`].Attrs[gographviz.Color])
+		assert.Equal(t, `"#2e7d32"`, dg.Edges.SrcToDsts[`"aws_lb.front"`][`"aws_instance.web"`][0].Attrs[gographviz.Color])
+		assert.Equal(t, `"#c62828"`, dg.Edges.SrcToDsts[`"aws_lb.front"`][`"aws_instance.old"`][0].Attrs[gographviz.Color])
+	})
+}
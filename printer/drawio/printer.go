@@ -0,0 +1,185 @@
+package drawio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/layout"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	// cellWidth and cellHeight are the space
+	// each Node has on the layout
+	cellWidth  = 220
+	cellHeight = 150
+
+	// iconSize is the size of the shapes with icons
+	iconSize = 48
+
+	// shapeWidth and shapeHeight are the size
+	// of the shapes without icons
+	shapeWidth  = 120
+	shapeHeight = 48
+
+	// rootID and layerID are the IDs of the two
+	// cells that every mxGraph needs
+	rootID  = "0"
+	layerID = "1"
+
+	edgeStyle = "edgeStyle=orthogonalEdgeStyle;rounded=1;endArrow=classic;"
+)
+
+// DrawIO is the struct that implements the Printer
+// of the draw.io/diagrams.net (mxGraph) format
+type DrawIO struct{}
+
+type mxFile struct {
+	XMLName xml.Name  `xml:"mxfile"`
+	Host    string    `xml:"host,attr"`
+	Diagram mxDiagram `xml:"diagram"`
+}
+
+type mxDiagram struct {
+	ID    string       `xml:"id,attr"`
+	Name  string       `xml:"name,attr"`
+	Model mxGraphModel `xml:"mxGraphModel"`
+}
+
+type mxGraphModel struct {
+	Grid       int      `xml:"grid,attr"`
+	GridSize   int      `xml:"gridSize,attr"`
+	Arrows     int      `xml:"arrows,attr"`
+	PageWidth  int      `xml:"pageWidth,attr"`
+	PageHeight int      `xml:"pageHeight,attr"`
+	Cells      []mxCell `xml:"root>mxCell"`
+}
+
+type mxCell struct {
+	ID       string      `xml:"id,attr"`
+	Value    string      `xml:"value,attr,omitempty"`
+	Style    string      `xml:"style,attr,omitempty"`
+	Vertex   string      `xml:"vertex,attr,omitempty"`
+	Edge     string      `xml:"edge,attr,omitempty"`
+	Parent   string      `xml:"parent,attr,omitempty"`
+	Source   string      `xml:"source,attr,omitempty"`
+	Target   string      `xml:"target,attr,omitempty"`
+	Geometry *mxGeometry `xml:"mxGeometry,omitempty"`
+}
+
+type mxGeometry struct {
+	X        int    `xml:"x,attr,omitempty"`
+	Y        int    `xml:"y,attr,omitempty"`
+	Width    int    `xml:"width,attr,omitempty"`
+	Height   int    `xml:"height,attr,omitempty"`
+	Relative string `xml:"relative,attr,omitempty"`
+	As       string `xml:"as,attr"`
+}
+
+// Print prints into w the g as a draw.io diagram
+func (d DrawIO) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	l := layout.Layered(g, cellWidth, cellHeight)
+
+	cells := []mxCell{
+		{ID: rootID},
+		{ID: layerID, Parent: rootID},
+	}
+
+	// icons holds the already encoded icons
+	// so we do not encode them more than once
+	icons := make(map[string]string)
+
+	for _, n := range printer.SortNodes(g.Nodes) {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		p := l.Positions[n.ID]
+		c := mxCell{
+			ID:     n.ID,
+			Value:  printer.NodeLabel(n, opt),
+			Vertex: "1",
+			Parent: layerID,
+		}
+
+		if opt.ShowIcons && n.Resource.Icon != "" {
+			ip := printer.IconPath(pv.Type(), n.Resource.Icon)
+			if _, ok := icons[ip]; !ok {
+				icon, err := printer.IconDataURI(pv.Type(), n.Resource.Icon)
+				if err != nil {
+					return err
+				}
+				// The ';' is the separator of the style so draw.io
+				// expects the data URIs without the ';base64'
+				icons[ip] = strings.Replace(icon, ";base64,", ",", 1)
+			}
+			c.Style = fmt.Sprintf("shape=image;verticalLabelPosition=bottom;verticalAlign=top;imageAspect=0;aspect=fixed;image=%s;", icons[ip])
+			c.Geometry = &mxGeometry{X: p.X - iconSize/2, Y: p.Y - iconSize/2, Width: iconSize, Height: iconSize, As: "geometry"}
+		} else {
+			c.Style = "ellipse;"
+			if pv.IsEdge(rs) {
+				c.Style = "rounded=1;"
+			}
+			c.Geometry = &mxGeometry{X: p.X - shapeWidth/2, Y: p.Y - shapeHeight/2, Width: shapeWidth, Height: shapeHeight, As: "geometry"}
+		}
+
+		cells = append(cells, c)
+	}
+
+	for _, e := range g.Edges {
+		if _, ok := l.Positions[e.Source]; !ok {
+			return fmt.Errorf("edge source %q: %w", e.Source, errcode.ErrGraphNotFoundNode)
+		}
+		if _, ok := l.Positions[e.Target]; !ok {
+			return fmt.Errorf("edge target %q: %w", e.Target, errcode.ErrGraphNotFoundNode)
+		}
+
+		cells = append(cells, mxCell{
+			ID:       e.ID,
+			Style:    edgeStyle,
+			Edge:     "1",
+			Parent:   layerID,
+			Source:   e.Source,
+			Target:   e.Target,
+			Geometry: &mxGeometry{Relative: "1", As: "geometry"},
+		})
+	}
+
+	f := mxFile{
+		Host: "inframap",
+		Diagram: mxDiagram{
+			ID:   "inframap",
+			Name: "InfraMap",
+			Model: mxGraphModel{
+				Grid:       1,
+				GridSize:   10,
+				Arrows:     1,
+				PageWidth:  l.Width,
+				PageHeight: l.Height,
+				Cells:      cells,
+			},
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,90 @@
+package drawio_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/drawio"
+)
+
+type mxFile struct {
+	Cells []struct {
+		ID     string `xml:"id,attr"`
+		Value  string `xml:"value,attr"`
+		Style  string `xml:"style,attr"`
+		Vertex string `xml:"vertex,attr"`
+		Edge   string `xml:"edge,attr"`
+		Parent string `xml:"parent,attr"`
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+	} `xml:"diagram>mxGraphModel>root>mxCell"`
+}
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "n1", Canonical: "aws_lb.front", Name: "<front>"}
+	n2 := &graph.Node{ID: "n2", Canonical: "aws_instance.front"}
+	n2.Resource.Icon = "Compute/Amazon-EC2.png"
+	e1 := &graph.Edge{ID: "e1", Source: n1.ID, Target: n2.ID}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddEdge(e1))
+
+	t.Run("Success", func(t *testing.T) {
+		var b bytes.Buffer
+		err := drawio.DrawIO{}.Print(g, printer.Options{AlternativeNodeNames: true}, &b)
+		require.NoError(t, err)
+
+		var f mxFile
+		require.NoError(t, xml.Unmarshal(b.Bytes(), &f))
+		require.Len(t, f.Cells, 5)
+
+		assert.Equal(t, "0", f.Cells[0].ID)
+		assert.Equal(t, "1", f.Cells[1].ID)
+
+		assert.Equal(t, "n2", f.Cells[2].ID)
+		assert.Equal(t, "aws_instance.front", f.Cells[2].Value)
+		assert.Equal(t, "1", f.Cells[2].Vertex)
+		assert.Equal(t, "1", f.Cells[2].Parent)
+
+		assert.Equal(t, "n1", f.Cells[3].ID)
+		assert.Equal(t, "<front>", f.Cells[3].Value)
+
+		assert.Equal(t, "e1", f.Cells[4].ID)
+		assert.Equal(t, "1", f.Cells[4].Edge)
+		assert.Equal(t, "n1", f.Cells[4].Source)
+		assert.Equal(t, "n2", f.Cells[4].Target)
+	})
+	t.Run("SuccessShowIcons", func(t *testing.T) {
+		var b bytes.Buffer
+		err := drawio.DrawIO{}.Print(g, printer.Options{ShowIcons: true}, &b)
+		require.NoError(t, err)
+
+		var f mxFile
+		require.NoError(t, xml.Unmarshal(b.Bytes(), &f))
+		require.Len(t, f.Cells, 5)
+
+		assert.Contains(t, f.Cells[2].Style, "shape=image;")
+		assert.Contains(t, f.Cells[2].Style, "image=data:image/png,")
+		assert.False(t, strings.Contains(f.Cells[2].Style, ";base64"))
+		assert.NotContains(t, f.Cells[3].Style, "shape=image;")
+	})
+	t.Run("ErrorNotFoundNode", func(t *testing.T) {
+		g := graph.New()
+		g.Edges = append(g.Edges, &graph.Edge{ID: "1", Source: "1", Target: "2"})
+
+		var b bytes.Buffer
+		err := drawio.DrawIO{}.Print(g, printer.Options{}, &b)
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundNode))
+	})
+}
@@ -5,12 +5,32 @@ import (
 
 	"github.com/cycloidio/inframap/errcode"
 	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/ascii"
 	"github.com/cycloidio/inframap/printer/dot"
+	"github.com/cycloidio/inframap/printer/drawio"
+	"github.com/cycloidio/inframap/printer/gexf"
+	"github.com/cycloidio/inframap/printer/graphml"
+	"github.com/cycloidio/inframap/printer/html"
+	"github.com/cycloidio/inframap/printer/json"
+	"github.com/cycloidio/inframap/printer/mermaid"
+	"github.com/cycloidio/inframap/printer/plantuml"
+	"github.com/cycloidio/inframap/printer/png"
+	"github.com/cycloidio/inframap/printer/svg"
 )
 
 var (
 	printers = map[printer.Type]printer.Printer{
-		printer.DOT: dot.Dot{},
+		printer.DOT:      dot.Dot{},
+		printer.JSON:     json.JSON{},
+		printer.Mermaid:  mermaid.Mermaid{},
+		printer.PlantUML: plantuml.PlantUML{},
+		printer.HTML:     html.HTML{},
+		printer.SVG:      svg.SVG{},
+		printer.PNG:      png.PNG{},
+		printer.DrawIO:   drawio.DrawIO{},
+		printer.GraphML:  graphml.GraphML{},
+		printer.GEXF:     gexf.GEXF{},
+		printer.ASCII:    ascii.ASCII{},
 	}
 )
 
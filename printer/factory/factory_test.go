@@ -8,14 +8,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/printer"
 	"github.com/cycloidio/inframap/printer/factory"
 )
 
 func TestGet(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		p, err := factory.Get("dot")
-		require.NoError(t, err)
-		assert.NotNil(t, p)
+		for _, pt := range printer.TypeStrings() {
+			p, err := factory.Get(pt)
+			require.NoError(t, err, pt)
+			assert.NotNil(t, p, pt)
+		}
 	})
 	t.Run("Error", func(t *testing.T) {
 		p, err := factory.Get("potato")
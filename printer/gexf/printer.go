@@ -0,0 +1,156 @@
+package gexf
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// GEXF is the struct that implements
+// the Printer of GEXF format
+type GEXF struct{}
+
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    meta      `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type meta struct {
+	Creator string `xml:"creator"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string       `xml:"defaultedgetype,attr"`
+	Mode            string       `xml:"mode,attr"`
+	Attributes      []attributes `xml:"attributes"`
+	Nodes           []gexfNode   `xml:"nodes>node"`
+	Edges           []gexfEdge   `xml:"edges>edge"`
+}
+
+type attributes struct {
+	Class      string      `xml:"class,attr"`
+	Attributes []attribute `xml:"attribute"`
+}
+
+type attribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string     `xml:"id,attr"`
+	Label     string     `xml:"label,attr"`
+	AttValues []attValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string     `xml:"id,attr"`
+	Source    string     `xml:"source,attr"`
+	Target    string     `xml:"target,attr"`
+	AttValues []attValue `xml:"attvalues>attvalue"`
+}
+
+type attValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// attrs are all the attributes that the
+// Nodes and Edges have
+var attrs = []attributes{
+	{
+		Class: "node",
+		Attributes: []attribute{
+			{ID: "canonical", Title: "canonical", Type: "string"},
+			{ID: "name", Title: "name", Type: "string"},
+			{ID: "tfid", Title: "tfid", Type: "string"},
+			{ID: "provider", Title: "provider", Type: "string"},
+			{ID: "type", Title: "type", Type: "string"},
+			{ID: "category", Title: "category", Type: "string"},
+			{ID: "module", Title: "module", Type: "string"},
+			{ID: "weight", Title: "weight", Type: "integer"},
+		},
+	},
+	{
+		Class: "edge",
+		Attributes: []attribute{
+			{ID: "canonicals", Title: "canonicals", Type: "liststring"},
+		},
+	},
+}
+
+// Print prints into w the g in GEXF format
+func (gx GEXF) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	gf := gexf{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Meta:    meta{Creator: "InfraMap"},
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes:      attrs,
+			Nodes:           make([]gexfNode, 0, len(g.Nodes)),
+			Edges:           make([]gexfEdge, 0, len(g.Edges)),
+		},
+	}
+
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		gf.Graph.Nodes = append(gf.Graph.Nodes, gexfNode{
+			ID:    n.ID,
+			Label: printer.NodeLabel(n, opt),
+			AttValues: []attValue{
+				{For: "canonical", Value: n.Canonical},
+				{For: "name", Value: n.Name},
+				{For: "tfid", Value: n.TFID},
+				{For: "provider", Value: pv.Type().String()},
+				{For: "type", Value: n.Resource.Type},
+				{For: "category", Value: n.Resource.Category},
+				{For: "module", Value: n.Module()},
+				{For: "weight", Value: strconv.Itoa(n.Weight)},
+			},
+		})
+	}
+
+	for _, e := range g.Edges {
+		gf.Graph.Edges = append(gf.Graph.Edges, gexfEdge{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+			AttValues: []attValue{
+				// The liststring values are
+				// separated by '|'
+				{For: "canonicals", Value: strings.Join(e.Canonicals, "|")},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(gf); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
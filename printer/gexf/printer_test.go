@@ -0,0 +1,88 @@
+package gexf_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/gexf"
+)
+
+type attValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfFile struct {
+	Version string `xml:"version,attr"`
+	Graph   struct {
+		DefaultEdgeType string `xml:"defaultedgetype,attr"`
+		Nodes           []struct {
+			ID        string     `xml:"id,attr"`
+			Label     string     `xml:"label,attr"`
+			AttValues []attValue `xml:"attvalues>attvalue"`
+		} `xml:"nodes>node"`
+		Edges []struct {
+			ID        string     `xml:"id,attr"`
+			Source    string     `xml:"source,attr"`
+			Target    string     `xml:"target,attr"`
+			AttValues []attValue `xml:"attvalues>attvalue"`
+		} `xml:"edges>edge"`
+	} `xml:"graph"`
+}
+
+func toMap(avs []attValue) map[string]string {
+	m := make(map[string]string)
+	for _, av := range avs {
+		m[av.For] = av.Value
+	}
+	return m
+}
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "module.a.aws_lb.front", Name: "front", TFID: "lb-1", Weight: 2}
+	n1.Resource.Type = "aws_lb"
+	n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front", TFID: "i-1"}
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID, Canonicals: []string{"aws_security_group.a", "aws_security_group.b"}}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddEdge(e1))
+
+	t.Run("Success", func(t *testing.T) {
+		var b bytes.Buffer
+		err := gexf.GEXF{}.Print(g, printer.Options{AlternativeNodeNames: true}, &b)
+		require.NoError(t, err)
+
+		var gf gexfFile
+		require.NoError(t, xml.Unmarshal(b.Bytes(), &gf))
+
+		assert.Equal(t, "1.3", gf.Version)
+		assert.Equal(t, "directed", gf.Graph.DefaultEdgeType)
+
+		require.Len(t, gf.Graph.Nodes, 2)
+		assert.Equal(t, "front", gf.Graph.Nodes[0].Label)
+		assert.Equal(t, map[string]string{
+			"canonical": "module.a.aws_lb.front",
+			"name":      "front",
+			"tfid":      "lb-1",
+			"provider":  "aws",
+			"type":      "aws_lb",
+			"category":  "",
+			"module":    "module.a",
+			"weight":    "2",
+		}, toMap(gf.Graph.Nodes[0].AttValues))
+		assert.Equal(t, "aws_instance.front", gf.Graph.Nodes[1].Label)
+
+		require.Len(t, gf.Graph.Edges, 1)
+		assert.Equal(t, "1", gf.Graph.Edges[0].Source)
+		assert.Equal(t, "2", gf.Graph.Edges[0].Target)
+		assert.Equal(t, "aws_security_group.a|aws_security_group.b", toMap(gf.Graph.Edges[0].AttValues)["canonicals"])
+	})
+}
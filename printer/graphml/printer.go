@@ -0,0 +1,134 @@
+package graphml
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// GraphML is the struct that implements
+// the Printer of GraphML format
+type GraphML struct{}
+
+type graphML struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []key    `xml:"key"`
+	Graph   gmlGraph `xml:"graph"`
+}
+
+type key struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type gmlGraph struct {
+	ID          string    `xml:"id,attr"`
+	EdgeDefault string    `xml:"edgedefault,attr"`
+	Nodes       []gmlNode `xml:"node"`
+	Edges       []gmlEdge `xml:"edge"`
+}
+
+type gmlNode struct {
+	ID   string `xml:"id,attr"`
+	Data []data `xml:"data"`
+}
+
+type gmlEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []data `xml:"data"`
+}
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// keys are all the attributes that the Nodes
+// and Edges have
+var keys = []key{
+	{ID: "label", For: "node", Name: "label", Type: "string"},
+	{ID: "canonical", For: "node", Name: "canonical", Type: "string"},
+	{ID: "name", For: "node", Name: "name", Type: "string"},
+	{ID: "tfid", For: "node", Name: "tfid", Type: "string"},
+	{ID: "provider", For: "node", Name: "provider", Type: "string"},
+	{ID: "type", For: "node", Name: "type", Type: "string"},
+	{ID: "category", For: "node", Name: "category", Type: "string"},
+	{ID: "module", For: "node", Name: "module", Type: "string"},
+	{ID: "weight", For: "node", Name: "weight", Type: "int"},
+	{ID: "canonicals", For: "edge", Name: "canonicals", Type: "string"},
+}
+
+// Print prints into w the g in GraphML format
+func (gm GraphML) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	gml := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  keys,
+		Graph: gmlGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+			Nodes:       make([]gmlNode, 0, len(g.Nodes)),
+			Edges:       make([]gmlEdge, 0, len(g.Edges)),
+		},
+	}
+
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		gml.Graph.Nodes = append(gml.Graph.Nodes, gmlNode{
+			ID: n.ID,
+			Data: []data{
+				{Key: "label", Value: printer.NodeLabel(n, opt)},
+				{Key: "canonical", Value: n.Canonical},
+				{Key: "name", Value: n.Name},
+				{Key: "tfid", Value: n.TFID},
+				{Key: "provider", Value: pv.Type().String()},
+				{Key: "type", Value: n.Resource.Type},
+				{Key: "category", Value: n.Resource.Category},
+				{Key: "module", Value: n.Module()},
+				{Key: "weight", Value: strconv.Itoa(n.Weight)},
+			},
+		})
+	}
+
+	for _, e := range g.Edges {
+		gml.Graph.Edges = append(gml.Graph.Edges, gmlEdge{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+			Data: []data{
+				// GraphML has no list type so they
+				// are joined by ','
+				{Key: "canonicals", Value: strings.Join(e.Canonicals, ",")},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(gml); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
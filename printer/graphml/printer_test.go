@@ -0,0 +1,96 @@
+package graphml_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/graphml"
+)
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphML struct {
+	Keys []struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Type string `xml:"attr.type,attr"`
+	} `xml:"key"`
+	Graph struct {
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Nodes       []struct {
+			ID   string `xml:"id,attr"`
+			Data []data `xml:"data"`
+		} `xml:"node"`
+		Edges []struct {
+			ID     string `xml:"id,attr"`
+			Source string `xml:"source,attr"`
+			Target string `xml:"target,attr"`
+			Data   []data `xml:"data"`
+		} `xml:"edge"`
+	} `xml:"graph"`
+}
+
+func toMap(ds []data) map[string]string {
+	m := make(map[string]string)
+	for _, d := range ds {
+		m[d.Key] = d.Value
+	}
+	return m
+}
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "module.a.aws_lb.front", Name: "front", TFID: "lb-1", Weight: 2}
+	n1.Resource.Type = "aws_lb"
+	n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front", TFID: "i-1"}
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID, Canonicals: []string{"aws_security_group.a", "aws_security_group.b"}}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddEdge(e1))
+
+	t.Run("Success", func(t *testing.T) {
+		var b bytes.Buffer
+		err := graphml.GraphML{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		var gml graphML
+		require.NoError(t, xml.Unmarshal(b.Bytes(), &gml))
+
+		assert.Equal(t, "directed", gml.Graph.EdgeDefault)
+		for _, k := range gml.Keys {
+			if k.ID == "weight" {
+				assert.Equal(t, "int", k.Type)
+			}
+		}
+
+		require.Len(t, gml.Graph.Nodes, 2)
+		assert.Equal(t, "1", gml.Graph.Nodes[0].ID)
+		assert.Equal(t, map[string]string{
+			"label":     "module.a.aws_lb.front",
+			"canonical": "module.a.aws_lb.front",
+			"name":      "front",
+			"tfid":      "lb-1",
+			"provider":  "aws",
+			"type":      "aws_lb",
+			"category":  "",
+			"module":    "module.a",
+			"weight":    "2",
+		}, toMap(gml.Graph.Nodes[0].Data))
+		assert.Equal(t, "", toMap(gml.Graph.Nodes[1].Data)["module"])
+
+		require.Len(t, gml.Graph.Edges, 1)
+		assert.Equal(t, "1", gml.Graph.Edges[0].Source)
+		assert.Equal(t, "2", gml.Graph.Edges[0].Target)
+		assert.Equal(t, "aws_security_group.a,aws_security_group.b", toMap(gml.Graph.Edges[0].Data)["canonicals"])
+	})
+}
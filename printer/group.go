@@ -0,0 +1,100 @@
+package printer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cycloidio/inframap/graph"
+)
+
+// GroupTree holds the content of each graph.Group
+// so it can be printed recursively
+type GroupTree struct {
+	// Groups holds graph.Group.ID -> []*graph.Group
+	// with the Groups it contains, the Groups without
+	// Parent are on the "" key
+	Groups map[string][]*graph.Group
+
+	// Nodes holds graph.Group.ID -> []*graph.Node
+	// with the Nodes it contains, the Nodes without
+	// Group are on the "" key
+	Nodes map[string][]*graph.Node
+}
+
+// NewGroupTree returns the GroupTree of g, all the
+// Groups and Nodes on it are sorted by Canonical.
+// If a Group or Node references a Group that it's not
+// on the g it'll be considered as it had none
+func NewGroupTree(g *graph.Graph) *GroupTree {
+	gt := &GroupTree{
+		Groups: make(map[string][]*graph.Group),
+		Nodes:  make(map[string][]*graph.Node),
+	}
+
+	for _, gr := range SortGroups(g.Groups) {
+		p := gr.Parent
+		if _, err := g.GetGroupByID(p); err != nil {
+			p = ""
+		}
+		gt.Groups[p] = append(gt.Groups[p], gr)
+	}
+
+	for _, n := range SortNodes(g.Nodes) {
+		gID := n.Group
+		if _, err := g.GetGroupByID(gID); err != nil {
+			gID = ""
+		}
+		gt.Nodes[gID] = append(gt.Nodes[gID], n)
+	}
+
+	return gt
+}
+
+// SortGroups returns a copy of the groups sorted
+// by Canonical, so the printed output is stable
+// between executions
+func SortGroups(groups []*graph.Group) []*graph.Group {
+	sorted := make([]*graph.Group, len(groups))
+	copy(sorted, groups)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Canonical < sorted[j].Canonical })
+	return sorted
+}
+
+// GroupIDs returns the graph.Group.ID -> ID map in which the
+// ID is derived from the Canonical with only [a-zA-Z0-9_]
+// characters and prefixed with 'group_'. The nodeIDs, from
+// NodeIDs, are used to not repeat any of them
+func GroupIDs(groups []*graph.Group, nodeIDs map[string]string) map[string]string {
+	ids := make(map[string]string)
+
+	// usedIDs holds all the IDs already assigned
+	usedIDs := make(map[string]struct{})
+	for _, id := range nodeIDs {
+		usedIDs[id] = struct{}{}
+	}
+
+	for _, gr := range SortGroups(groups) {
+		base := "group_" + reInvalidID.ReplaceAllString(gr.Canonical, "_")
+		id := base
+		for i := 2; ; i++ {
+			if _, ok := usedIDs[id]; !ok {
+				break
+			}
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		usedIDs[id] = struct{}{}
+		ids[gr.ID] = id
+	}
+
+	return ids
+}
+
+// GroupLabel returns the label to use for the gr, by default it's
+// the Canonical but if opt.AlternativeNodeNames is set it'll be
+// the Name if it has one
+func GroupLabel(gr *graph.Group, opt Options) string {
+	if opt.AlternativeNodeNames && gr.Name != "" {
+		return gr.Name
+	}
+	return gr.Canonical
+}
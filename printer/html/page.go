@@ -0,0 +1,265 @@
+package html
+
+// page is the template of the HTML page, it has
+// all the styles and JS inlined so it does not
+// need any external resource to work
+const page = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>InfraMap</title>
+<style>
+  html, body { margin: 0; height: 100%; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; }
+  body { display: flex; flex-direction: column; }
+  header { display: flex; align-items: center; gap: 1em; padding: .5em 1em; border-bottom: 1px solid #ddd; background: #fafafa; }
+  header h1 { font-size: 1.1em; margin: 0; }
+  header input { flex: 0 1 24em; padding: .3em .5em; }
+  header span { color: #777; font-size: .85em; }
+  main { flex: 1; display: flex; min-height: 0; }
+  #graph { flex: 1; cursor: grab; background: #fff; }
+  #graph.panning { cursor: grabbing; }
+  aside { width: 24em; overflow: auto; border-left: 1px solid #ddd; padding: 0 1em; font-size: .9em; }
+  aside dt { font-weight: bold; margin-top: .6em; }
+  aside dd { margin: 0; word-break: break-all; }
+  aside pre { background: #f5f5f5; padding: .5em; overflow: auto; font-size: .85em; }
+  aside ul { padding-left: 1.2em; }
+  .edge { stroke: #888; stroke-width: 1.5; }
+  .node { cursor: pointer; }
+  .node text { font-size: 12px; fill: #222; }
+  .node ellipse, .node rect { fill: #fff; stroke: #555; stroke-width: 1.5; }
+  .node .halo { fill: none; stroke: none; stroke-width: 3; }
+  .node.match .halo { stroke: #f0a30a; }
+  .node.selected .halo { stroke: #1f6feb; }
+  .node.selected text { font-weight: bold; }
+  .dimmed { opacity: .2; }
+</style>
+</head>
+<body>
+<header>
+  <h1>InfraMap</h1>
+  <input id="search" type="search" placeholder="Search by canonical or name" autocomplete="off">
+  <span>Drag to pan, scroll to zoom, click a node to inspect it</span>
+</header>
+<main>
+  <svg id="graph" xmlns="http://www.w3.org/2000/svg">
+    <defs>
+      <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse">
+        <path d="M 0 0 L 10 5 L 0 10 z" fill="#888"></path>
+      </marker>
+    </defs>
+    <g id="viewport">
+      <g id="edges"></g>
+      <g id="nodes"></g>
+    </g>
+  </svg>
+  <aside id="details"><p>Select a node to see its details.</p></aside>
+</main>
+<script>
+(function () {
+  "use strict";
+
+  var data = {{.Data}};
+  var svgNS = "http://www.w3.org/2000/svg";
+  var radius = 34;
+
+  var svg = document.getElementById("graph");
+  var viewport = document.getElementById("viewport");
+  var details = document.getElementById("details");
+  var search = document.getElementById("search");
+
+  var nodes = {};
+  var nodeEdges = {};
+  var view = { x: 0, y: 0, k: 1 };
+  var drag = null;
+  var selected = null;
+
+  function el(name, attrs, parent) {
+    var e = document.createElementNS(svgNS, name);
+    Object.keys(attrs).forEach(function (k) { e.setAttribute(k, attrs[k]); });
+    parent.appendChild(e);
+    return e;
+  }
+
+  function applyView() {
+    viewport.setAttribute("transform", "translate(" + view.x + "," + view.y + ") scale(" + view.k + ")");
+  }
+
+  function placeNode(n) {
+    n.el.setAttribute("transform", "translate(" + n.x + "," + n.y + ")");
+  }
+
+  // placeEdge draws the edge from border to border of
+  // the nodes so the arrow is visible
+  function placeEdge(e) {
+    var s = nodes[e.source], t = nodes[e.target];
+    var dx = t.x - s.x, dy = t.y - s.y;
+    var len = Math.sqrt(dx * dx + dy * dy) || 1;
+    var ux = dx / len, uy = dy / len;
+    e.el.setAttribute("x1", s.x + ux * radius);
+    e.el.setAttribute("y1", s.y + uy * radius);
+    e.el.setAttribute("x2", t.x - ux * radius);
+    e.el.setAttribute("y2", t.y - uy * radius);
+  }
+
+  function text(parent, tag, value) {
+    var e = document.createElement(tag);
+    e.textContent = value;
+    parent.appendChild(e);
+    return e;
+  }
+
+  function select(n) {
+    if (selected) { selected.el.classList.remove("selected"); }
+    selected = n;
+    n.el.classList.add("selected");
+
+    details.innerHTML = "";
+    text(details, "h2", n.label);
+    var dl = document.createElement("dl");
+    [["Canonical", n.canonical], ["Name", n.name], ["TFID", n.tfid], ["Type", n.type], ["Provider", n.provider]].forEach(function (f) {
+      if (!f[1]) { return; }
+      text(dl, "dt", f[0]);
+      text(dl, "dd", f[1]);
+    });
+    details.appendChild(dl);
+
+    var edges = nodeEdges[n.id] || [];
+    if (edges.length) {
+      text(details, "h3", "Connections");
+      var ul = document.createElement("ul");
+      edges.forEach(function (e) {
+        var other = e.source === n.id ? "→ " + nodes[e.target].label : "← " + nodes[e.source].label;
+        var li = text(ul, "li", other);
+        if (e.canonicals.length) {
+          li.appendChild(document.createElement("br"));
+          text(li, "small", "via " + e.canonicals.join(", "));
+        }
+      });
+      details.appendChild(ul);
+    }
+
+    if (n.description && Object.keys(n.description).length) {
+      text(details, "h3", "Description");
+      text(details, "pre", JSON.stringify(n.description, null, 2));
+    }
+  }
+
+  function focus(n) {
+    var r = svg.getBoundingClientRect();
+    view.x = r.width / 2 - n.x * view.k;
+    view.y = r.height / 2 - n.y * view.k;
+    applyView();
+    select(n);
+  }
+
+  function fit() {
+    var r = svg.getBoundingClientRect();
+    var margin = 40;
+    view.k = Math.min(1, (r.width - 2 * margin) / (data.width || 1), (r.height - 2 * margin) / (data.height || 1));
+    view.x = (r.width - data.width * view.k) / 2;
+    view.y = (r.height - data.height * view.k) / 2;
+    applyView();
+  }
+
+  data.edges.forEach(function (e) {
+    e.el = el("line", { "class": "edge", "marker-end": "url(#arrow)" }, document.getElementById("edges"));
+    (nodeEdges[e.source] = nodeEdges[e.source] || []).push(e);
+    if (e.target !== e.source) {
+      (nodeEdges[e.target] = nodeEdges[e.target] || []).push(e);
+    }
+  });
+
+  data.nodes.forEach(function (n) {
+    nodes[n.id] = n;
+    n.el = el("g", { "class": "node" }, document.getElementById("nodes"));
+    el("circle", { "class": "halo", r: radius + 2 }, n.el);
+    if (n.icon) {
+      el("image", { href: n.icon, x: -24, y: -24, width: 48, height: 48 }, n.el);
+    } else if (n.is_edge) {
+      el("rect", { x: -30, y: -18, width: 60, height: 36 }, n.el);
+    } else {
+      el("ellipse", { rx: 30, ry: 18 }, n.el);
+    }
+    el("text", { y: radius + 14, "text-anchor": "middle" }, n.el).textContent = n.label;
+    el("title", {}, n.el).textContent = n.canonical;
+    placeNode(n);
+
+    n.el.addEventListener("mousedown", function (ev) {
+      ev.stopPropagation();
+      drag = { node: n, sx: ev.clientX, sy: ev.clientY, ox: n.x, oy: n.y, moved: false };
+    });
+  });
+
+  data.edges.forEach(placeEdge);
+
+  svg.addEventListener("mousedown", function (ev) {
+    drag = { sx: ev.clientX, sy: ev.clientY, ox: view.x, oy: view.y };
+    svg.classList.add("panning");
+  });
+
+  window.addEventListener("mousemove", function (ev) {
+    if (!drag) { return; }
+    var dx = ev.clientX - drag.sx, dy = ev.clientY - drag.sy;
+    if (!drag.node) {
+      view.x = drag.ox + dx;
+      view.y = drag.oy + dy;
+      applyView();
+      return;
+    }
+    if (Math.abs(dx) + Math.abs(dy) > 3) { drag.moved = true; }
+    drag.node.x = drag.ox + dx / view.k;
+    drag.node.y = drag.oy + dy / view.k;
+    placeNode(drag.node);
+    (nodeEdges[drag.node.id] || []).forEach(placeEdge);
+  });
+
+  window.addEventListener("mouseup", function () {
+    if (drag && drag.node && !drag.moved) { select(drag.node); }
+    drag = null;
+    svg.classList.remove("panning");
+  });
+
+  svg.addEventListener("wheel", function (ev) {
+    ev.preventDefault();
+    var factor = ev.deltaY < 0 ? 1.1 : 1 / 1.1;
+    var r = svg.getBoundingClientRect();
+    var px = ev.clientX - r.left, py = ev.clientY - r.top;
+    view.x = px - (px - view.x) * factor;
+    view.y = py - (py - view.y) * factor;
+    view.k *= factor;
+    applyView();
+  }, { passive: false });
+
+  function matches() {
+    var q = search.value.trim().toLowerCase();
+    return data.nodes.filter(function (n) {
+      return q && (n.canonical.toLowerCase().indexOf(q) !== -1 || n.name.toLowerCase().indexOf(q) !== -1);
+    });
+  }
+
+  search.addEventListener("input", function () {
+    var q = search.value.trim();
+    var found = {};
+    matches().forEach(function (n) { found[n.id] = true; });
+    data.nodes.forEach(function (n) {
+      n.el.classList.toggle("match", !!found[n.id]);
+      n.el.classList.toggle("dimmed", !!q && !found[n.id]);
+    });
+    data.edges.forEach(function (e) {
+      e.el.classList.toggle("dimmed", !!q && !(found[e.source] && found[e.target]));
+    });
+  });
+
+  search.addEventListener("keydown", function (ev) {
+    if (ev.key !== "Enter") { return; }
+    var m = matches();
+    if (m.length) { focus(m[0]); }
+  });
+
+  window.addEventListener("resize", fit);
+  fit();
+})();
+</script>
+</body>
+</html>
+`
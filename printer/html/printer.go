@@ -0,0 +1,140 @@
+package html
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/layout"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	// cellWidth and cellHeight are the space
+	// each Node has on the layout
+	cellWidth  = 220
+	cellHeight = 150
+)
+
+// HTML is the struct that implements the Printer
+// of a self-contained interactive HTML page
+type HTML struct{}
+
+// node is the representation of a graph.Node
+// that is sent to the page
+type node struct {
+	ID          string      `json:"id"`
+	Label       string      `json:"label"`
+	Canonical   string      `json:"canonical"`
+	Name        string      `json:"name"`
+	TFID        string      `json:"tfid"`
+	Type        string      `json:"type"`
+	Provider    string      `json:"provider"`
+	Icon        string      `json:"icon,omitempty"`
+	IsEdge      bool        `json:"is_edge"`
+	X           int         `json:"x"`
+	Y           int         `json:"y"`
+	Description interface{} `json:"description,omitempty"`
+}
+
+// edge is the representation of a graph.Edge
+// that is sent to the page
+type edge struct {
+	Source     string   `json:"source"`
+	Target     string   `json:"target"`
+	Canonicals []string `json:"canonicals"`
+}
+
+// data is all the information the page needs
+// to draw the graph
+type data struct {
+	Nodes  []node `json:"nodes"`
+	Edges  []edge `json:"edges"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Print prints into w the g as a self-contained HTML page
+func (h HTML) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	l := layout.Layered(g, cellWidth, cellHeight)
+
+	d := data{
+		Nodes:  make([]node, 0, len(g.Nodes)),
+		Edges:  make([]edge, 0, len(g.Edges)),
+		Width:  l.Width,
+		Height: l.Height,
+	}
+
+	// icons holds the already encoded icons
+	// so we do not embed them more than once
+	icons := make(map[string]string)
+
+	for _, n := range printer.SortNodes(g.Nodes) {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		hn := node{
+			ID:          n.ID,
+			Label:       printer.NodeLabel(n, opt),
+			Canonical:   n.Canonical,
+			Name:        n.Name,
+			TFID:        n.TFID,
+			Type:        n.Resource.Type,
+			Provider:    pv.Type().String(),
+			IsEdge:      pv.IsEdge(rs),
+			X:           l.Positions[n.ID].X,
+			Y:           l.Positions[n.ID].Y,
+			Description: opt.Description[n.Canonical],
+		}
+
+		if opt.ShowIcons && n.Resource.Icon != "" {
+			ip := printer.IconPath(pv.Type(), n.Resource.Icon)
+			if _, ok := icons[ip]; !ok {
+				icon, err := printer.IconDataURI(pv.Type(), n.Resource.Icon)
+				if err != nil {
+					return err
+				}
+				icons[ip] = icon
+			}
+			hn.Icon = icons[ip]
+		}
+
+		d.Nodes = append(d.Nodes, hn)
+	}
+
+	for _, e := range g.Edges {
+		cans := make([]string, 0, len(e.Canonicals))
+		cans = append(cans, e.Canonicals...)
+
+		d.Edges = append(d.Edges, edge{
+			Source:     e.Source,
+			Target:     e.Target,
+			Canonicals: cans,
+		})
+	}
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	t, err := template.New("html").Parse(page)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, struct {
+		Data template.JS
+	}{
+		// The json.Marshal escapes the HTML characters
+		// so it's safe to be used inside the script
+		Data: template.JS(b),
+	})
+}
@@ -0,0 +1,92 @@
+package html_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/html"
+)
+
+var reData = regexp.MustCompile(`var data = (.*);\n`)
+
+type data struct {
+	Nodes []struct {
+		ID          string                 `json:"id"`
+		Label       string                 `json:"label"`
+		Canonical   string                 `json:"canonical"`
+		Provider    string                 `json:"provider"`
+		Icon        string                 `json:"icon"`
+		IsEdge      bool                   `json:"is_edge"`
+		Description map[string]interface{} `json:"description"`
+	} `json:"nodes"`
+	Edges []struct {
+		Source     string   `json:"source"`
+		Target     string   `json:"target"`
+		Canonicals []string `json:"canonicals"`
+	} `json:"edges"`
+}
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front", Name: "</script>"}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front"}
+	n2.Resource.Icon = "Compute/Amazon-EC2.png"
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID, Canonicals: []string{"aws_security_group.front"}}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddEdge(e1))
+
+	print := func(t *testing.T, opt printer.Options) (string, data) {
+		var b bytes.Buffer
+		err := html.HTML{}.Print(g, opt, &b)
+		require.NoError(t, err)
+
+		m := reData.FindStringSubmatch(b.String())
+		require.Len(t, m, 2)
+
+		var d data
+		require.NoError(t, json.Unmarshal([]byte(m[1]), &d))
+		return b.String(), d
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		out, d := print(t, printer.Options{
+			Description: map[string]interface{}{
+				"aws_lb.front": map[string]interface{}{"name": "front"},
+			},
+		})
+
+		assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+		assert.NotContains(t, out, "<script src")
+		assert.Equal(t, 1, strings.Count(out, "</script>"))
+
+		require.Len(t, d.Nodes, 2)
+		assert.Equal(t, "aws_instance.front", d.Nodes[0].Canonical)
+		assert.Equal(t, "aws", d.Nodes[0].Provider)
+		assert.Empty(t, d.Nodes[0].Icon)
+		assert.Equal(t, "aws_lb.front", d.Nodes[1].Canonical)
+		assert.Equal(t, map[string]interface{}{"name": "front"}, d.Nodes[1].Description)
+
+		require.Len(t, d.Edges, 1)
+		assert.Equal(t, "1", d.Edges[0].Source)
+		assert.Equal(t, "2", d.Edges[0].Target)
+		assert.Equal(t, []string{"aws_security_group.front"}, d.Edges[0].Canonicals)
+	})
+	t.Run("SuccessShowIcons", func(t *testing.T) {
+		_, d := print(t, printer.Options{ShowIcons: true, AlternativeNodeNames: true})
+
+		require.Len(t, d.Nodes, 2)
+		assert.True(t, strings.HasPrefix(d.Nodes[0].Icon, "data:image/png;base64,"))
+		assert.Empty(t, d.Nodes[1].Icon)
+		assert.Equal(t, "</script>", d.Nodes[1].Label)
+	})
+}
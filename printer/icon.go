@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/markbates/pkger"
+	"github.com/markbates/pkger/pkging"
+
+	// As we require to load the assets to be used
+	// we import it as empty
+	_ "github.com/cycloidio/inframap/assets"
+	"github.com/cycloidio/inframap/provider"
+)
+
+// IconPath returns the path of the PNG version of the icon of
+// the Provider t, relative to the assets icons directory.
+// The icons on the resources are defined as SVG but the
+// ones we embed are PNG
+func IconPath(t provider.Type, icon string) string {
+	ext := path.Ext(icon)
+	return path.Join(t.String(), fmt.Sprintf("%s.png", icon[0:len(icon)-len(ext)]))
+}
+
+// OpenIcon opens the embedded PNG version of the icon
+// of the Provider t
+func OpenIcon(t provider.Type, icon string) (pkging.File, error) {
+	return pkger.Open(path.Join("/assets", "icons", IconPath(t, icon)))
+}
+
+// IconDataURI returns the embedded PNG version of the icon
+// of the Provider t encoded as a base64 data URI, so it can be
+// inlined on the outputs that have to be self-contained
+func IconDataURI(t provider.Type, icon string) (string, error) {
+	f, err := OpenIcon(t, icon)
+	if err != nil {
+		return "", fmt.Errorf("could not open icon %q: %w", icon, err)
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return "", fmt.Errorf("could not read icon %q: %w", icon, err)
+	}
+
+	return fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(b)), nil
+}
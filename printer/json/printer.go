@@ -0,0 +1,167 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// Version is the version of the JSON schema printed,
+// it'll be increased every time a breaking change is
+// done to the schema
+const Version = 1
+
+// JSON is the struct that implements
+// the Printer of JSON format
+type JSON struct{}
+
+// Graph is the JSON representation of the graph.Graph
+type Graph struct {
+	Version int     `json:"version"`
+	Nodes   []Node  `json:"nodes"`
+	Edges   []Edge  `json:"edges"`
+	Groups  []Group `json:"groups"`
+}
+
+// Node is the JSON representation of the graph.Node
+type Node struct {
+	ID        string   `json:"id"`
+	Canonical string   `json:"canonical"`
+	Name      string   `json:"name"`
+	TFID      string   `json:"tfid"`
+	Provider  string   `json:"provider"`
+	Resource  Resource `json:"resource"`
+	Weight    int      `json:"weight"`
+
+	// Group is the ID of the Group
+	// that contains the Node
+	Group string `json:"group,omitempty"`
+
+	// Instances is the number of instances
+	// the Node represents, if collapsed
+	Instances int `json:"instances,omitempty"`
+
+	// Action is the change planned for the Node,
+	// if generated from a plan, or the one on a Diff
+	Action string `json:"action,omitempty"`
+}
+
+// Resource is the JSON representation of the
+// information of the resource a Node holds
+type Resource struct {
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	Icon     string `json:"icon"`
+}
+
+// Group is the JSON representation of the graph.Group,
+// the Parent is the ID of the Group that contains it
+type Group struct {
+	ID        string   `json:"id"`
+	Canonical string   `json:"canonical"`
+	Name      string   `json:"name"`
+	TFID      string   `json:"tfid"`
+	Provider  string   `json:"provider"`
+	Resource  Resource `json:"resource"`
+	Parent    string   `json:"parent,omitempty"`
+}
+
+// Edge is the JSON representation of the graph.Edge,
+// the Source and Target are the IDs of the Nodes
+type Edge struct {
+	ID         string   `json:"id"`
+	Source     string   `json:"source"`
+	Target     string   `json:"target"`
+	Canonicals []string `json:"canonicals"`
+
+	// Action is the change of
+	// the Edge on a Diff
+	Action string `json:"action,omitempty"`
+}
+
+// Print prints into w the g in JSON format
+func (j JSON) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	jg := Graph{
+		Version: Version,
+		Nodes:   make([]Node, 0, len(g.Nodes)),
+		Edges:   make([]Edge, 0, len(g.Edges)),
+		Groups:  make([]Group, 0, len(g.Groups)),
+	}
+
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		jn := Node{
+			ID:        n.ID,
+			Canonical: n.Canonical,
+			Name:      n.Name,
+			TFID:      n.TFID,
+			Provider:  pv.Type().String(),
+			Resource: Resource{
+				Type:     n.Resource.Type,
+				Category: n.Resource.Category,
+				Icon:     n.Resource.Icon,
+			},
+			Weight:    n.Weight,
+			Group:     n.Group,
+			Instances: n.Instances,
+		}
+		if n.Action != graph.ActionNone {
+			jn.Action = n.Action.String()
+		}
+
+		jg.Nodes = append(jg.Nodes, jn)
+	}
+
+	for _, e := range g.Edges {
+		cans := make([]string, 0, len(e.Canonicals))
+		cans = append(cans, e.Canonicals...)
+
+		je := Edge{
+			ID:         e.ID,
+			Source:     e.Source,
+			Target:     e.Target,
+			Canonicals: cans,
+		}
+		if e.Action != graph.ActionNone {
+			je.Action = e.Action.String()
+		}
+
+		jg.Edges = append(jg.Edges, je)
+	}
+
+	for _, gr := range g.Groups {
+		pv, _, _ := factory.GetProviderAndResource(gr.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		jg.Groups = append(jg.Groups, Group{
+			ID:        gr.ID,
+			Canonical: gr.Canonical,
+			Name:      gr.Name,
+			TFID:      gr.TFID,
+			Provider:  pv.Type().String(),
+			Resource: Resource{
+				Type:     gr.Resource.Type,
+				Category: gr.Resource.Category,
+				Icon:     gr.Resource.Icon,
+			},
+			Parent: gr.Parent,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(jg)
+}
@@ -0,0 +1,61 @@
+package json_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cycloidio/tfdocs/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	pjson "github.com/cycloidio/inframap/printer/json"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front", Name: "front", TFID: "lb-1", Resource: resource.Resource{Type: "aws_lb", Category: "Networking", Icon: "lb.svg"}}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.web", TFID: "i-1", Weight: 1, Group: "1"}
+		gr := &graph.Group{ID: "1", Canonical: "aws_subnet.public", Name: "public", TFID: "subnet-1", Resource: resource.Resource{Type: "aws_subnet"}}
+		e := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+		e.AddCanonicals("aws_security_group.front")
+
+		require.NoError(t, g.AddGroup(gr))
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e))
+
+		var b bytes.Buffer
+		err := pjson.JSON{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		var jg pjson.Graph
+		err = json.Unmarshal(b.Bytes(), &jg)
+		require.NoError(t, err)
+
+		assert.Equal(t, pjson.Graph{
+			Version: pjson.Version,
+			Nodes: []pjson.Node{
+				pjson.Node{
+					ID: "1", Canonical: "aws_lb.front", Name: "front", TFID: "lb-1", Provider: "aws",
+					Resource: pjson.Resource{Type: "aws_lb", Category: "Networking", Icon: "lb.svg"},
+				},
+				pjson.Node{
+					ID: "2", Canonical: "aws_instance.web", TFID: "i-1", Provider: "aws", Weight: 1, Group: "1",
+				},
+			},
+			Groups: []pjson.Group{
+				pjson.Group{
+					ID: "1", Canonical: "aws_subnet.public", Name: "public", TFID: "subnet-1", Provider: "aws",
+					Resource: pjson.Resource{Type: "aws_subnet"},
+				},
+			},
+			Edges: []pjson.Edge{
+				pjson.Edge{ID: "1", Source: "1", Target: "2", Canonicals: []string{"aws_security_group.front"}},
+			},
+		}, jg)
+	})
+}
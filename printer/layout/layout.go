@@ -0,0 +1,235 @@
+// Package layout computes the positions of the Nodes of a
+// graph.Graph for the printers that render the graph by
+// themselves instead of delegating it to an external tool
+// like Graphviz.
+package layout
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+)
+
+// sweeps is the number of times the order of the layers
+// is recalculated to reduce the crossing of the edges
+const sweeps = 4
+
+// Point is a position on the Layout
+type Point struct {
+	X int
+	Y int
+}
+
+// Layout holds the position of each Node
+// of a graph.Graph
+type Layout struct {
+	// Positions holds the center of each Node
+	// as graph.Node.ID -> Point
+	Positions map[string]Point
+
+	// Width and Height are the total size
+	// needed to draw all the Nodes
+	Width  int
+	Height int
+}
+
+// Layered returns the Layout of g in which each Node is placed on a
+// cell of cellWidth x cellHeight. The Nodes are distributed in layers
+// following the direction of the Edges (the Sources are above the Targets)
+// and the Nodes without any Edge are placed at the bottom
+func Layered(g *graph.Graph, cellWidth, cellHeight int) *Layout {
+	nodes := printer.SortNodes(g.Nodes)
+
+	// outs and ins hold graph.Node.ID -> []graph.Node.ID
+	outs := make(map[string][]string)
+	ins := make(map[string][]string)
+	for _, e := range g.Edges {
+		// Self referencing edges do not change the layout
+		if e.Source == e.Target {
+			continue
+		}
+		outs[e.Source] = append(outs[e.Source], e.Target)
+		ins[e.Target] = append(ins[e.Target], e.Source)
+	}
+
+	connected := make([]*graph.Node, 0, len(nodes))
+	isolated := make([]*graph.Node, 0)
+	for _, n := range nodes {
+		if len(outs[n.ID]) == 0 && len(ins[n.ID]) == 0 {
+			isolated = append(isolated, n)
+		} else {
+			connected = append(connected, n)
+		}
+	}
+
+	layers := assignLayers(connected, outs)
+	orderLayers(layers, outs, ins)
+
+	width := 0
+	for _, l := range layers {
+		if len(l) > width {
+			width = len(l)
+		}
+	}
+
+	// The isolated ones are placed on a grid at
+	// the bottom trying to not make it wider than
+	// the rest of the graph
+	if len(isolated) > 0 {
+		cols := width
+		if sq := int(math.Ceil(math.Sqrt(float64(len(isolated))))); sq > cols {
+			cols = sq
+		}
+		for i := 0; i < len(isolated); i += cols {
+			end := i + cols
+			if end > len(isolated) {
+				end = len(isolated)
+			}
+			layers = append(layers, isolated[i:end])
+		}
+		width = cols
+	}
+
+	l := &Layout{
+		Positions: make(map[string]Point),
+		Width:     width * cellWidth,
+		Height:    len(layers) * cellHeight,
+	}
+
+	for y, layer := range layers {
+		// Each layer is centered
+		offset := (width - len(layer)) * cellWidth / 2
+		for x, n := range layer {
+			l.Positions[n.ID] = Point{
+				X: offset + x*cellWidth + cellWidth/2,
+				Y: y*cellHeight + cellHeight/2,
+			}
+		}
+	}
+
+	return l
+}
+
+// assignLayers returns the nodes distributed in layers, in which each
+// Node is one layer below the lowest of the Nodes pointing to it.
+// The cycles are ignored by not following the edges that
+// go back to a Node that is already being visited
+func assignLayers(nodes []*graph.Node, outs map[string][]string) [][]*graph.Node {
+	byID := make(map[string]*graph.Node)
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+
+	// dag holds the outs without the edges that make cycles
+	dag := make(map[string][]string)
+
+	// state is 1 while visiting and 2 once visited
+	state := make(map[string]int)
+
+	// order is the reverse topological order
+	order := make([]string, 0, len(nodes))
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = 1
+		for _, t := range outs[id] {
+			if state[t] == 1 {
+				continue
+			}
+			dag[id] = append(dag[id], t)
+			if state[t] == 0 {
+				visit(t)
+			}
+		}
+		state[id] = 2
+		order = append(order, id)
+	}
+
+	for _, n := range nodes {
+		if state[n.ID] == 0 {
+			visit(n.ID)
+		}
+	}
+
+	layerOf := make(map[string]int)
+	max := 0
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		for _, t := range dag[id] {
+			if layerOf[id]+1 > layerOf[t] {
+				layerOf[t] = layerOf[id] + 1
+				if layerOf[t] > max {
+					max = layerOf[t]
+				}
+			}
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	layers := make([][]*graph.Node, max+1)
+	for _, n := range nodes {
+		layers[layerOf[n.ID]] = append(layers[layerOf[n.ID]], byID[n.ID])
+	}
+
+	return layers
+}
+
+// orderLayers sorts each layer by the average position of the Nodes
+// connected to it on the other layers, which reduces the number of
+// edges crossing each other
+func orderLayers(layers [][]*graph.Node, outs, ins map[string][]string) {
+	// index holds graph.Node.ID -> index on the layer
+	index := make(map[string]int)
+	for _, l := range layers {
+		for i, n := range l {
+			index[n.ID] = i
+		}
+	}
+
+	sortLayer := func(l []*graph.Node, neighbours map[string][]string) {
+		bary := make(map[string]float64)
+		for _, n := range l {
+			ns := neighbours[n.ID]
+			if len(ns) == 0 {
+				bary[n.ID] = float64(index[n.ID])
+				continue
+			}
+			var sum int
+			for _, nid := range ns {
+				sum += index[nid]
+			}
+			bary[n.ID] = float64(sum) / float64(len(ns))
+		}
+		sort.SliceStable(l, func(i, j int) bool { return bary[l[i].ID] < bary[l[j].ID] })
+		for i, n := range l {
+			index[n.ID] = i
+		}
+	}
+
+	for s := 0; s < sweeps; s++ {
+		for i := 1; i < len(layers); i++ {
+			sortLayer(layers[i], ins)
+		}
+		for i := len(layers) - 2; i >= 0; i-- {
+			sortLayer(layers[i], outs)
+		}
+	}
+}
+
+// Segment returns the line that goes from a to b leaving
+// a gap of margin on both ends, so it does not overlap
+// with the Nodes drawn on them
+func Segment(a, b Point, margin float64) (x1, y1, x2, y2 float64) {
+	dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return float64(a.X), float64(a.Y), float64(b.X), float64(b.Y)
+	}
+	ux, uy := dx/l, dy/l
+	return float64(a.X) + ux*margin, float64(a.Y) + uy*margin, float64(b.X) - ux*margin, float64(b.Y) - uy*margin
+}
@@ -0,0 +1,88 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer/layout"
+)
+
+func TestLayered(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "can1"}
+		n2 := &graph.Node{ID: "2", Canonical: "can2"}
+		n3 := &graph.Node{ID: "3", Canonical: "can3"}
+		n4 := &graph.Node{ID: "4", Canonical: "can4"}
+		n5 := &graph.Node{ID: "5", Canonical: "can5"}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+		e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+		e3 := &graph.Edge{ID: "3", Source: n1.ID, Target: n3.ID}
+		e4 := &graph.Edge{ID: "4", Source: n1.ID, Target: n4.ID}
+
+		for _, n := range []*graph.Node{n1, n2, n3, n4, n5} {
+			require.NoError(t, g.AddNode(n))
+		}
+		for _, e := range []*graph.Edge{e1, e2, e3, e4} {
+			require.NoError(t, g.AddEdge(e))
+		}
+
+		l := layout.Layered(g, 100, 50)
+
+		assert.Len(t, l.Positions, 5)
+		assert.Equal(t, 200, l.Width)
+		assert.Equal(t, 200, l.Height)
+
+		// The Targets are always below the Sources
+		for _, e := range g.Edges {
+			assert.Less(t, l.Positions[e.Source].Y, l.Positions[e.Target].Y)
+		}
+
+		// The isolated Node is on the last layer
+		assert.Equal(t, 175, l.Positions[n5.ID].Y)
+
+		// No Nodes share position
+		seen := make(map[layout.Point]struct{})
+		for _, p := range l.Positions {
+			_, ok := seen[p]
+			assert.False(t, ok)
+			seen[p] = struct{}{}
+		}
+	})
+	t.Run("SuccessCyclic", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "can1"}
+		n2 := &graph.Node{ID: "2", Canonical: "can2"}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+		e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n1.ID}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e1))
+		require.NoError(t, g.AddEdge(e2))
+
+		l := layout.Layered(g, 100, 50)
+
+		assert.Len(t, l.Positions, 2)
+		assert.NotEqual(t, l.Positions[n1.ID].Y, l.Positions[n2.ID].Y)
+	})
+	t.Run("SuccessEmpty", func(t *testing.T) {
+		l := layout.Layered(graph.New(), 100, 50)
+		assert.Len(t, l.Positions, 0)
+		assert.Equal(t, 0, l.Width)
+	})
+}
+
+func TestSegment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		x1, y1, x2, y2 := layout.Segment(layout.Point{X: 0, Y: 0}, layout.Point{X: 0, Y: 100}, 10)
+		assert.Equal(t, []float64{0, 10, 0, 90}, []float64{x1, y1, x2, y2})
+	})
+	t.Run("SuccessSamePoint", func(t *testing.T) {
+		x1, y1, x2, y2 := layout.Segment(layout.Point{X: 5, Y: 5}, layout.Point{X: 5, Y: 5}, 10)
+		assert.Equal(t, []float64{5, 5, 5, 5}, []float64{x1, y1, x2, y2})
+	})
+}
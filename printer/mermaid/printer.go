@@ -0,0 +1,92 @@
+package mermaid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// Mermaid is the struct that implements
+// the Printer of Mermaid flowchart format
+type Mermaid struct{}
+
+// Print prints into w the g in Mermaid flowchart format
+func (m Mermaid) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "flowchart TB")
+
+	// ids holds the graph.Node.ID -> Mermaid ID
+	ids := printer.NodeIDs(g.Nodes)
+
+	// gids holds the graph.Group.ID -> Mermaid ID
+	gids := printer.GroupIDs(g.Groups, ids)
+
+	printGroup(bw, printer.NewGroupTree(g), "", ids, gids, opt, "    ")
+
+	edges := make([]string, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		src, ok := ids[e.Source]
+		if !ok {
+			return fmt.Errorf("edge source %q: %w", e.Source, errcode.ErrGraphNotFoundNode)
+		}
+
+		tr, ok := ids[e.Target]
+		if !ok {
+			return fmt.Errorf("edge target %q: %w", e.Target, errcode.ErrGraphNotFoundNode)
+		}
+
+		edges = append(edges, fmt.Sprintf("%s --> %s", src, tr))
+	}
+	sort.Strings(edges)
+
+	for _, e := range edges {
+		fmt.Fprintf(bw, "    %s\n", e)
+	}
+
+	return bw.Flush()
+}
+
+// printGroup prints the Nodes and the Groups, as subgraphs, contained on
+// the Group with the gID and then recursively the content of those Groups
+func printGroup(w io.Writer, gt *printer.GroupTree, gID string, ids, gids map[string]string, opt printer.Options, indent string) {
+	for _, gr := range gt.Groups[gID] {
+		fmt.Fprintf(w, "%ssubgraph %s [\"%s\"]\n", indent, gids[gr.ID], escapeLabel(printer.GroupLabel(gr, opt)))
+		printGroup(w, gt, gr.ID, ids, gids, opt, indent+"    ")
+		fmt.Fprintf(w, "%send\n", indent)
+	}
+
+	for _, n := range gt.Nodes[gID] {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		// The Edges are displayed as rectangles and the
+		// Nodes as stadiums (the closest to an ellipse)
+		if pv.IsEdge(rs) {
+			fmt.Fprintf(w, "%s%s[\"%s\"]\n", indent, ids[n.ID], escapeLabel(printer.NodeLabel(n, opt)))
+		} else {
+			fmt.Fprintf(w, "%s%s([\"%s\"])\n", indent, ids[n.ID], escapeLabel(printer.NodeLabel(n, opt)))
+		}
+	}
+}
+
+// escapeLabel escapes the characters that would
+// break a quoted Mermaid label
+func escapeLabel(l string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"\n", " ",
+	).Replace(l)
+}
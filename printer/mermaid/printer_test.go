@@ -0,0 +1,90 @@
+package mermaid_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/mermaid"
+)
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/80->80"}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_lb.front", Name: `the "front"`}
+	n3 := &graph.Node{ID: "3", Canonical: "aws_security_group.front"}
+	n4 := &graph.Node{ID: "4", Canonical: "aws_lb-front"}
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+	e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+	e3 := &graph.Edge{ID: "3", Source: n4.ID, Target: n3.ID}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddNode(n3))
+	require.NoError(t, g.AddNode(n4))
+	require.NoError(t, g.AddEdge(e1))
+	require.NoError(t, g.AddEdge(e2))
+	require.NoError(t, g.AddEdge(e3))
+
+	t.Run("Success", func(t *testing.T) {
+		var b bytes.Buffer
+		err := mermaid.Mermaid{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		assert.Equal(t, `flowchart TB
+    aws_lb_front(["aws_lb-front"])
+    aws_lb_front_2(["aws_lb.front"])
+    aws_security_group_front["aws_security_group.front"]
+    im_out_tcp_80__80(["im_out.tcp/80->80"])
+    aws_lb_front --> aws_security_group_front
+    aws_lb_front_2 --> aws_security_group_front
+    im_out_tcp_80__80 --> aws_lb_front_2
+`, b.String())
+	})
+	t.Run("SuccessAlternativeNodeNames", func(t *testing.T) {
+		var b bytes.Buffer
+		err := mermaid.Mermaid{}.Print(g, printer.Options{AlternativeNodeNames: true}, &b)
+		require.NoError(t, err)
+
+		assert.Contains(t, b.String(), `aws_lb_front_2(["the #quot;front#quot;"])`)
+		assert.Contains(t, b.String(), `aws_lb_front(["aws_lb-front"])`)
+	})
+	t.Run("SuccessGroups", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "aws_vpc.main"}
+		gr2 := &graph.Group{ID: "2", Canonical: "aws_subnet.public", Name: "public", Parent: gr1.ID}
+		n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front", Group: gr2.ID}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_db_instance.db", Group: gr1.ID}
+		n3 := &graph.Node{ID: "3", Canonical: "im_out.tcp/80->80"}
+		e1 := &graph.Edge{ID: "1", Source: n3.ID, Target: n1.ID}
+		e2 := &graph.Edge{ID: "2", Source: n1.ID, Target: n2.ID}
+
+		require.NoError(t, g.AddGroup(gr1))
+		require.NoError(t, g.AddGroup(gr2))
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddNode(n3))
+		require.NoError(t, g.AddEdge(e1))
+		require.NoError(t, g.AddEdge(e2))
+
+		var b bytes.Buffer
+		err := mermaid.Mermaid{}.Print(g, printer.Options{AlternativeNodeNames: true}, &b)
+		require.NoError(t, err)
+
+		assert.Equal(t, `flowchart TB
+    subgraph group_aws_vpc_main ["aws_vpc.main"]
+        subgraph group_aws_subnet_public ["public"]
+            aws_lb_front(["aws_lb.front"])
+        end
+        aws_db_instance_db(["aws_db_instance.db"])
+    end
+    im_out_tcp_80__80(["im_out.tcp/80->80"])
+    aws_lb_front --> aws_db_instance_db
+    im_out_tcp_80__80 --> aws_lb_front
+`, b.String())
+	})
+}
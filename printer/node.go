@@ -0,0 +1,88 @@
+package printer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/cycloidio/inframap/graph"
+)
+
+// reInvalidID matches all the characters that
+// are not safe to be used as an identifier
+var reInvalidID = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// SortNodes returns a copy of the nodes sorted
+// by Canonical, so the printed output is stable
+// between executions
+func SortNodes(nodes []*graph.Node) []*graph.Node {
+	sorted := make([]*graph.Node, len(nodes))
+	copy(sorted, nodes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Canonical < sorted[j].Canonical })
+	return sorted
+}
+
+// NodeIDs returns the graph.Node.ID -> ID map in which the
+// ID is derived from the Canonical with only [a-zA-Z0-9_]
+// characters. If 2 Canonicals escape to the same ID the
+// second one will be suffixed with an index
+func NodeIDs(nodes []*graph.Node) map[string]string {
+	ids := make(map[string]string)
+
+	// usedIDs holds all the IDs already assigned
+	usedIDs := make(map[string]struct{})
+
+	for _, n := range SortNodes(nodes) {
+		base := reInvalidID.ReplaceAllString(n.Canonical, "_")
+		id := base
+		for i := 2; ; i++ {
+			if _, ok := usedIDs[id]; !ok {
+				break
+			}
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		usedIDs[id] = struct{}{}
+		ids[n.ID] = id
+	}
+
+	return ids
+}
+
+// NodeLabel returns the label to use for the n, by default it's
+// the Canonical but if opt.AlternativeNodeNames is set it'll be
+// the Name if it has one. If the n represents multiple instances
+// the number of them is added at the end, like ' (x3)'
+func NodeLabel(n *graph.Node, opt Options) string {
+	l := n.Canonical
+	if opt.AlternativeNodeNames && n.Name != "" {
+		l = n.Name
+	}
+	if n.Instances > 1 {
+		l = fmt.Sprintf("%s (x%d)", l, n.Instances)
+	}
+	return l
+}
+
+// actionColors holds the graph.Action -> color used to
+// display the change planned for a Node or the change
+// of a Node or Edge on a Diff
+var actionColors = map[graph.Action]string{
+	graph.ActionCreate:  "#2e7d32",
+	graph.ActionUpdate:  "#ef6c00",
+	graph.ActionDelete:  "#c62828",
+	graph.ActionReplace: "#6a1b9a",
+}
+
+// unchangedColor is the color of the
+// elements not changed on a Diff
+const unchangedColor = "#9e9e9e"
+
+// ActionColor returns the color of the Action a, if it's
+// graph.ActionNone it returns an empty string or, if the
+// opt.Diff is set, the grey of the elements not changed
+func ActionColor(a graph.Action, opt Options) string {
+	if a == graph.ActionNone && opt.Diff {
+		return unchangedColor
+	}
+	return actionColors[a]
+}
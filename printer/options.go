@@ -9,4 +9,23 @@ type Options struct {
 
 	// AlternativeNodeNames will use Node names instead of canonical names
 	AlternativeNodeNames bool
+
+	// Description is the configuration of the elements
+	// of the Graph by canonical, as returned by the
+	// generate. It's used by the printers that can
+	// display the details of each Node
+	Description map[string]interface{}
+
+	// Color toggles the ANSI colors on the
+	// printers that output to a terminal
+	Color bool
+
+	// ClusterModules groups the Nodes in clusters
+	// following the module hierarchy of them
+	ClusterModules bool
+
+	// Diff means that the Graph is the one of
+	// graph.Changes, so the Nodes and Edges without
+	// Action are displayed as not changed
+	Diff bool
 }
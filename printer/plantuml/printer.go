@@ -0,0 +1,137 @@
+package plantuml
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// PlantUML is the struct that implements
+// the Printer of PlantUML deployment diagram format
+type PlantUML struct{}
+
+var (
+	// databaseKeywords are the keywords that, if present on the resource
+	// category or type, will render the Node as a 'database'
+	databaseKeywords = []string{
+		"database", "rds", "sql", "dynamodb", "docdb", "cosmosdb", "elasticache",
+		"redis", "memcache", "memorystore", "neptune", "redshift", "bigtable",
+		"bigquery", "spanner", "firestore", "gaussdb", "dax",
+	}
+
+	// queueKeywords are the keywords that, if present on the resource
+	// category or type, will render the Node as a 'queue'
+	queueKeywords = []string{
+		"queue", "messag", "pub/sub", "pubsub", "sqs", "sns", "mq_", "kinesis",
+		"eventbridge", "eventhub", "servicebus", "notification",
+	}
+)
+
+// Print prints into w the g in PlantUML deployment diagram format
+func (p PlantUML) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "@startuml")
+
+	// ids holds the graph.Node.ID -> PlantUML alias
+	ids := printer.NodeIDs(g.Nodes)
+
+	// gids holds the graph.Group.ID -> PlantUML alias
+	gids := printer.GroupIDs(g.Groups, ids)
+
+	printGroup(bw, printer.NewGroupTree(g), "", ids, gids, opt, "")
+
+	edges := make([]string, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		src, ok := ids[e.Source]
+		if !ok {
+			return fmt.Errorf("edge source %q: %w", e.Source, errcode.ErrGraphNotFoundNode)
+		}
+
+		tr, ok := ids[e.Target]
+		if !ok {
+			return fmt.Errorf("edge target %q: %w", e.Target, errcode.ErrGraphNotFoundNode)
+		}
+
+		edges = append(edges, fmt.Sprintf("%s --> %s", src, tr))
+	}
+	sort.Strings(edges)
+
+	for _, e := range edges {
+		fmt.Fprintln(bw, e)
+	}
+
+	fmt.Fprintln(bw, "@enduml")
+
+	return bw.Flush()
+}
+
+// printGroup prints the Nodes and the Groups, as rectangles, contained on
+// the Group with the gID and then recursively the content of those Groups
+func printGroup(w io.Writer, gt *printer.GroupTree, gID string, ids, gids map[string]string, opt printer.Options, indent string) {
+	for _, gr := range gt.Groups[gID] {
+		fmt.Fprintf(w, "%srectangle \"%s\" as %s {\n", indent, escapeLabel(printer.GroupLabel(gr, opt)), gids[gr.ID])
+		printGroup(w, gt, gr.ID, ids, gids, opt, indent+"  ")
+		fmt.Fprintf(w, "%s}\n", indent)
+	}
+
+	for _, n := range gt.Nodes[gID] {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		fmt.Fprintf(w, "%s%s \"%s\" as %s\n", indent, element(pv, rs, n), escapeLabel(printer.NodeLabel(n, opt)), ids[n.ID])
+	}
+}
+
+// element returns the PlantUML deployment element that
+// better represents the n based on the resource category
+func element(pv provider.Provider, rs string, n *graph.Node) string {
+	// The IM ones are the external Nodes,
+	// basically internet
+	if pv.Type() == provider.IM {
+		return "cloud"
+	}
+
+	// The Edges are only visible if the Connections are not
+	// applied, and they are displayed as rectangles like on DOT
+	if pv.IsEdge(rs) {
+		return "rectangle"
+	}
+
+	// The category is the main source, but some of them are too generic
+	// (like 'Resources') so we also check the type of the resource
+	desc := strings.ToLower(n.Resource.Category + " " + n.Resource.Type)
+	for _, k := range databaseKeywords {
+		if strings.Contains(desc, k) {
+			return "database"
+		}
+	}
+	for _, k := range queueKeywords {
+		if strings.Contains(desc, k) {
+			return "queue"
+		}
+	}
+
+	return "node"
+}
+
+// escapeLabel removes the characters that would
+// break a quoted PlantUML name
+func escapeLabel(l string) string {
+	return strings.NewReplacer(
+		`"`, "'",
+		"\n", " ",
+	).Replace(l)
+}
@@ -0,0 +1,79 @@
+package plantuml_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/tfdocs/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/plantuml"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/80->80"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front", Resource: resource.Resource{Type: "aws_instance", Category: "EC2 (Elastic Compute Cloud)"}}
+		n3 := &graph.Node{ID: "3", Canonical: "aws_db_instance.app", Resource: resource.Resource{Type: "aws_db_instance", Category: "RDS (Relational Database)"}}
+		n4 := &graph.Node{ID: "4", Canonical: "aws_sqs_queue.jobs", Resource: resource.Resource{Type: "aws_sqs_queue", Category: "SQS (Simple Queue)"}}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+		e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+		e3 := &graph.Edge{ID: "3", Source: n2.ID, Target: n4.ID}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddNode(n3))
+		require.NoError(t, g.AddNode(n4))
+		require.NoError(t, g.AddEdge(e1))
+		require.NoError(t, g.AddEdge(e2))
+		require.NoError(t, g.AddEdge(e3))
+
+		var b bytes.Buffer
+		err := plantuml.PlantUML{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		assert.Equal(t, `@startuml
+database "aws_db_instance.app" as aws_db_instance_app
+node "aws_instance.front" as aws_instance_front
+queue "aws_sqs_queue.jobs" as aws_sqs_queue_jobs
+cloud "im_out.tcp/80->80" as im_out_tcp_80__80
+aws_instance_front --> aws_db_instance_app
+aws_instance_front --> aws_sqs_queue_jobs
+im_out_tcp_80__80 --> aws_instance_front
+@enduml
+`, b.String())
+	})
+	t.Run("SuccessGroups", func(t *testing.T) {
+		g := graph.New()
+		gr1 := &graph.Group{ID: "1", Canonical: "aws_vpc.main"}
+		gr2 := &graph.Group{ID: "2", Canonical: "aws_subnet.public", Parent: gr1.ID}
+		n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/80->80"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front", Group: gr2.ID}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+
+		require.NoError(t, g.AddGroup(gr1))
+		require.NoError(t, g.AddGroup(gr2))
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e1))
+
+		var b bytes.Buffer
+		err := plantuml.PlantUML{}.Print(g, printer.Options{}, &b)
+		require.NoError(t, err)
+
+		assert.Equal(t, `@startuml
+rectangle "aws_vpc.main" as group_aws_vpc_main {
+  rectangle "aws_subnet.public" as group_aws_subnet_public {
+    node "aws_instance.front" as aws_instance_front
+  }
+}
+cloud "im_out.tcp/80->80" as im_out_tcp_80__80
+im_out_tcp_80__80 --> aws_instance_front
+@enduml
+`, b.String())
+	})
+}
@@ -0,0 +1,299 @@
+package png
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/layout"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	// cellWidth and cellHeight are the space
+	// each Node has on the layout
+	cellWidth  = 220
+	cellHeight = 150
+
+	// margin is the space left around the graph
+	margin = 20
+
+	// radius is the space around the center of a Node
+	// that the edges do not cross
+	radius = 40
+
+	// iconSize is the size in which the icons are drawn
+	iconSize = 48
+
+	// stroke is the width of the lines
+	stroke = 1.5
+
+	// arrowSize is the length of the arrow heads
+	arrowSize = 8
+)
+
+var (
+	background = color.White
+	foreground = color.RGBA{R: 0x55, G: 0x55, B: 0x55, A: 0xff}
+	text       = color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xff}
+)
+
+// PNG is the struct that implements the Printer
+// of a PNG image
+type PNG struct{}
+
+// point is a position with subpixel precision
+type point struct {
+	X float32
+	Y float32
+}
+
+// Print prints into w the g rendered as a PNG image
+func (p PNG) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	l := layout.Layered(g, cellWidth, cellHeight)
+
+	img := image.NewRGBA(image.Rect(0, 0, l.Width+2*margin, l.Height+2*margin))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	// pos returns the position of the Node
+	// nid on the image
+	pos := func(nid string) (layout.Point, bool) {
+		lp, ok := l.Positions[nid]
+		if !ok {
+			return lp, false
+		}
+		return layout.Point{X: lp.X + margin, Y: lp.Y + margin}, true
+	}
+
+	for _, e := range g.Edges {
+		src, ok := pos(e.Source)
+		if !ok {
+			return fmt.Errorf("edge source %q: %w", e.Source, errcode.ErrGraphNotFoundNode)
+		}
+		tgt, ok := pos(e.Target)
+		if !ok {
+			return fmt.Errorf("edge target %q: %w", e.Target, errcode.ErrGraphNotFoundNode)
+		}
+		x1, y1, x2, y2 := layout.Segment(src, tgt, radius)
+		a, b := point{X: float32(x1), Y: float32(y1)}, point{X: float32(x2), Y: float32(y2)}
+		fill(img, foreground, line(a, b, stroke))
+		fill(img, foreground, arrow(a, b))
+	}
+
+	// icons holds the already decoded icons
+	// so we do not decode them more than once
+	icons := make(map[string]image.Image)
+
+	face := basicfont.Face7x13
+
+	for _, n := range printer.SortNodes(g.Nodes) {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		c, _ := pos(n.ID)
+		center := point{X: float32(c.X), Y: float32(c.Y)}
+
+		if opt.ShowIcons && n.Resource.Icon != "" {
+			ip := printer.IconPath(pv.Type(), n.Resource.Icon)
+			if _, ok := icons[ip]; !ok {
+				icon, err := decodeIcon(pv.Type(), n.Resource.Icon)
+				if err != nil {
+					return err
+				}
+				icons[ip] = icon
+			}
+			r := image.Rect(c.X-iconSize/2, c.Y-iconSize/2, c.X+iconSize/2, c.Y+iconSize/2)
+			draw.CatmullRom.Scale(img, r, icons[ip], icons[ip].Bounds(), draw.Over, nil)
+		} else if pv.IsEdge(rs) {
+			fill(img, background, rectangle(center, 30, 18, 0))
+			fill(img, foreground, rectangle(center, 30, 18, stroke))
+		} else {
+			fill(img, background, ellipse(center, 30, 18, 0))
+			fill(img, foreground, ellipse(center, 30, 18, stroke))
+		}
+
+		label := printer.NodeLabel(n, opt)
+		d := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(text),
+			Face: face,
+		}
+		lw := d.MeasureString(label)
+		d.Dot = fixed.Point26_6{
+			X: fixed.I(c.X) - lw/2,
+			Y: fixed.I(c.Y + radius + 10),
+		}
+
+		// The background of the label hides
+		// the edges that cross it
+		lb, _ := font.BoundString(face, label)
+		draw.Draw(img, image.Rect(
+			(d.Dot.X+lb.Min.X).Floor()-2, (d.Dot.Y+lb.Min.Y).Floor()-1,
+			(d.Dot.X+lb.Max.X).Ceil()+2, (d.Dot.Y+lb.Max.Y).Ceil()+1,
+		), image.NewUniform(background), image.Point{}, draw.Src)
+		d.DrawString(label)
+	}
+
+	return png.Encode(w, img)
+}
+
+// decodeIcon returns the embedded icon
+// of the Provider t decoded
+func decodeIcon(t provider.Type, icon string) (image.Image, error) {
+	f, err := printer.OpenIcon(t, icon)
+	if err != nil {
+		return nil, fmt.Errorf("could not open icon %q: %w", icon, err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode icon %q: %w", icon, err)
+	}
+
+	return img, nil
+}
+
+// fill paints the polygons with c on dst, the polygons
+// that go on the opposite direction make holes on the
+// previous ones
+func fill(dst draw.Image, c color.Color, polygons [][]point) {
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX, maxY := float32(-math.MaxFloat32), float32(-math.MaxFloat32)
+	for _, pl := range polygons {
+		for _, pt := range pl {
+			if pt.X < minX {
+				minX = pt.X
+			}
+			if pt.Y < minY {
+				minY = pt.Y
+			}
+			if pt.X > maxX {
+				maxX = pt.X
+			}
+			if pt.Y > maxY {
+				maxY = pt.Y
+			}
+		}
+	}
+
+	// The rasterizer only covers the bounds
+	// of the polygons so it's cheaper
+	r := image.Rect(int(math.Floor(float64(minX))), int(math.Floor(float64(minY))), int(math.Ceil(float64(maxX))), int(math.Ceil(float64(maxY)))).Intersect(dst.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	z := vector.NewRasterizer(r.Dx(), r.Dy())
+	ox, oy := float32(r.Min.X), float32(r.Min.Y)
+	for _, pl := range polygons {
+		z.MoveTo(pl[0].X-ox, pl[0].Y-oy)
+		for _, pt := range pl[1:] {
+			z.LineTo(pt.X-ox, pt.Y-oy)
+		}
+		z.ClosePath()
+	}
+	z.Draw(dst, r, image.NewUniform(c), image.Point{})
+}
+
+// line returns the polygon of the line from a to b
+// with the width w
+func line(a, b point, w float32) [][]point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l := float32(math.Hypot(float64(dx), float64(dy)))
+	if l == 0 {
+		return nil
+	}
+	// The normal of the line of half the width
+	nx, ny := -dy/l*w/2, dx/l*w/2
+
+	return [][]point{{
+		{X: a.X + nx, Y: a.Y + ny},
+		{X: b.X + nx, Y: b.Y + ny},
+		{X: b.X - nx, Y: b.Y - ny},
+		{X: a.X - nx, Y: a.Y - ny},
+	}}
+}
+
+// arrow returns the polygon of the arrow head
+// of the line from a to b
+func arrow(a, b point) [][]point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l := float32(math.Hypot(float64(dx), float64(dy)))
+	if l == 0 {
+		return nil
+	}
+	ux, uy := dx/l, dy/l
+
+	return [][]point{{
+		b,
+		{X: b.X - ux*arrowSize - uy*arrowSize/2, Y: b.Y - uy*arrowSize + ux*arrowSize/2},
+		{X: b.X - ux*arrowSize + uy*arrowSize/2, Y: b.Y - uy*arrowSize - ux*arrowSize/2},
+	}}
+}
+
+// rectangle returns the polygons of the rectangle centered on c,
+// with the half sizes hw and hh. If w is 0 the rectangle is
+// filled, if not only the border of width w is returned
+func rectangle(c point, hw, hh, w float32) [][]point {
+	outer := []point{
+		{X: c.X - hw - w/2, Y: c.Y - hh - w/2},
+		{X: c.X + hw + w/2, Y: c.Y - hh - w/2},
+		{X: c.X + hw + w/2, Y: c.Y + hh + w/2},
+		{X: c.X - hw - w/2, Y: c.Y + hh + w/2},
+	}
+	if w == 0 {
+		return [][]point{outer}
+	}
+	inner := []point{
+		{X: c.X - hw + w/2, Y: c.Y - hh + w/2},
+		{X: c.X - hw + w/2, Y: c.Y + hh - w/2},
+		{X: c.X + hw - w/2, Y: c.Y + hh - w/2},
+		{X: c.X + hw - w/2, Y: c.Y - hh + w/2},
+	}
+	return [][]point{outer, inner}
+}
+
+// ellipse returns the polygons of the ellipse centered on c
+// with the radius rx and ry. If w is 0 the ellipse is
+// filled, if not only the border of width w is returned
+func ellipse(c point, rx, ry, w float32) [][]point {
+	const segments = 48
+
+	ring := func(rx, ry float32, reverse bool) []point {
+		pts := make([]point, 0, segments)
+		for i := 0; i < segments; i++ {
+			a := 2 * math.Pi * float64(i) / segments
+			if reverse {
+				a = -a
+			}
+			pts = append(pts, point{
+				X: c.X + rx*float32(math.Cos(a)),
+				Y: c.Y + ry*float32(math.Sin(a)),
+			})
+		}
+		return pts
+	}
+
+	if w == 0 {
+		return [][]point{ring(rx, ry, false)}
+	}
+	return [][]point{ring(rx+w/2, ry+w/2, false), ring(rx-w/2, ry-w/2, true)}
+}
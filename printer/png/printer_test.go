@@ -0,0 +1,51 @@
+package png_test
+
+import (
+	"bytes"
+	"image/color"
+	stdpng "image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/png"
+)
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front"}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front"}
+	n2.Resource.Icon = "Compute/Amazon-EC2.png"
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddEdge(e1))
+
+	tests := map[string]printer.Options{
+		"Success":          printer.Options{},
+		"SuccessShowIcons": printer.Options{ShowIcons: true},
+	}
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := png.PNG{}.Print(g, opt, &b)
+			require.NoError(t, err)
+
+			img, err := stdpng.Decode(&b)
+			require.NoError(t, err)
+
+			// One column and two layers plus the margins
+			assert.Equal(t, 260, img.Bounds().Dx())
+			assert.Equal(t, 340, img.Bounds().Dy())
+
+			// The corner is the background and the
+			// middle of the edge is drawn
+			assert.Equal(t, color.RGBAModel.Convert(color.White), color.RGBAModel.Convert(img.At(0, 0)))
+			assert.NotEqual(t, color.RGBAModel.Convert(color.White), color.RGBAModel.Convert(img.At(130, 170)))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package svg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/layout"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	// cellWidth and cellHeight are the space
+	// each Node has on the layout
+	cellWidth  = 220
+	cellHeight = 150
+
+	// margin is the space left around the graph
+	margin = 20
+
+	// radius is the space around the center of a Node
+	// that the edges do not cross
+	radius = 40
+
+	// iconSize is the size in which the icons are drawn
+	iconSize = 48
+)
+
+// SVG is the struct that implements the Printer
+// of an SVG image
+type SVG struct{}
+
+// Print prints into w the g rendered as an SVG image
+func (s SVG) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	l := layout.Layered(g, cellWidth, cellHeight)
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", l.Width+2*margin, l.Height+2*margin, l.Width+2*margin, l.Height+2*margin)
+	fmt.Fprintln(bw, `  <defs>`)
+	fmt.Fprintln(bw, `    <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" fill="#555555"/></marker>`)
+	fmt.Fprintln(bw, `  </defs>`)
+	fmt.Fprintln(bw, `  <rect width="100%" height="100%" fill="#ffffff"/>`)
+	fmt.Fprintf(bw, `  <g transform="translate(%d,%d)">`+"\n", margin, margin)
+
+	for _, e := range g.Edges {
+		src, ok := l.Positions[e.Source]
+		if !ok {
+			return fmt.Errorf("edge source %q: %w", e.Source, errcode.ErrGraphNotFoundNode)
+		}
+		tgt, ok := l.Positions[e.Target]
+		if !ok {
+			return fmt.Errorf("edge target %q: %w", e.Target, errcode.ErrGraphNotFoundNode)
+		}
+		x1, y1, x2, y2 := layout.Segment(src, tgt, radius)
+		fmt.Fprintf(bw, `    <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#555555" stroke-width="1.5" marker-end="url(#arrow)"/>`+"\n", x1, y1, x2, y2)
+	}
+
+	// icons holds the already encoded icons
+	// so we do not embed them more than once
+	icons := make(map[string]string)
+
+	for _, n := range printer.SortNodes(g.Nodes) {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		p := l.Positions[n.ID]
+
+		fmt.Fprintf(bw, `    <g transform="translate(%d,%d)">`+"\n", p.X, p.Y)
+		fmt.Fprintf(bw, `      <title>%s</title>`+"\n", escape(n.Canonical))
+
+		if opt.ShowIcons && n.Resource.Icon != "" {
+			ip := printer.IconPath(pv.Type(), n.Resource.Icon)
+			if _, ok := icons[ip]; !ok {
+				icon, err := printer.IconDataURI(pv.Type(), n.Resource.Icon)
+				if err != nil {
+					return err
+				}
+				icons[ip] = icon
+			}
+			fmt.Fprintf(bw, `      <image href="%s" x="%d" y="%d" width="%d" height="%d"/>`+"\n", icons[ip], -iconSize/2, -iconSize/2, iconSize, iconSize)
+		} else if pv.IsEdge(rs) {
+			fmt.Fprintln(bw, `      <rect x="-30" y="-18" width="60" height="36" fill="#ffffff" stroke="#555555" stroke-width="1.5"/>`)
+		} else {
+			fmt.Fprintln(bw, `      <ellipse rx="30" ry="18" fill="#ffffff" stroke="#555555" stroke-width="1.5"/>`)
+		}
+
+		fmt.Fprintf(bw, `      <text y="%d" text-anchor="middle" fill="#222222" stroke="#ffffff" stroke-width="4" paint-order="stroke">%s</text>`+"\n", radius+10, escape(printer.NodeLabel(n, opt)))
+		fmt.Fprintln(bw, `    </g>`)
+	}
+
+	fmt.Fprintln(bw, `  </g>`)
+	fmt.Fprintln(bw, `</svg>`)
+
+	return bw.Flush()
+}
+
+// escape returns s escaped to be used
+// as XML text
+func escape(s string) string {
+	var b bytes.Buffer
+	// It only fails if the writer fails
+	// which never happens with a bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package svg_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/svg"
+)
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front", Name: "<front>"}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front"}
+	n2.Resource.Icon = "Compute/Amazon-EC2.png"
+	n3 := &graph.Node{ID: "3", Canonical: "aws_security_group.front"}
+	e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+	e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddNode(n3))
+	require.NoError(t, g.AddEdge(e1))
+	require.NoError(t, g.AddEdge(e2))
+
+	t.Run("Success", func(t *testing.T) {
+		var b bytes.Buffer
+		err := svg.SVG{}.Print(g, printer.Options{AlternativeNodeNames: true}, &b)
+		require.NoError(t, err)
+
+		// It has to be a valid XML
+		d := xml.NewDecoder(strings.NewReader(b.String()))
+		for {
+			_, err := d.Token()
+			if err != nil {
+				assert.Equal(t, "EOF", err.Error())
+				break
+			}
+		}
+
+		out := b.String()
+		assert.Equal(t, 2, strings.Count(out, "<line "))
+		assert.Equal(t, 1, strings.Count(out, "<rect x="))
+		assert.Equal(t, 2, strings.Count(out, "<ellipse "))
+		assert.Contains(t, out, "&lt;front&gt;</text>")
+		assert.NotContains(t, out, "<image ")
+	})
+	t.Run("SuccessShowIcons", func(t *testing.T) {
+		var b bytes.Buffer
+		err := svg.SVG{}.Print(g, printer.Options{ShowIcons: true}, &b)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, strings.Count(b.String(), `<image href="data:image/png;base64,`))
+	})
+	t.Run("ErrorNotFoundNode", func(t *testing.T) {
+		g := graph.New()
+		g.Edges = append(g.Edges, &graph.Edge{ID: "1", Source: "1", Target: "2"})
+
+		var b bytes.Buffer
+		err := svg.SVG{}.Print(g, printer.Options{}, &b)
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundNode))
+	})
+}
@@ -8,4 +8,40 @@ type Type int
 // List of all Types
 const (
 	DOT Type = iota
+	JSON
+	Mermaid
+	PlantUML
+	HTML
+	SVG
+	PNG
+	DrawIO
+	GraphML
+	GEXF
+	ASCII
 )
+
+// Extension returns the extension, without the '.',
+// of the files with the output of the Printer
+func (t Type) Extension() string {
+	switch t {
+	case Mermaid:
+		return "mmd"
+	case PlantUML:
+		return "puml"
+	case ASCII:
+		return "txt"
+	default:
+		return t.String()
+	}
+}
+
+// SupportsGroups checks if the Printer draws the
+// Groups, the other ones ignore them
+func (t Type) SupportsGroups() bool {
+	switch t {
+	case DOT, JSON, Mermaid, PlantUML:
+		return true
+	default:
+		return false
+	}
+}
@@ -7,11 +7,11 @@ import (
 	"strings"
 )
 
-const _TypeName = "dot"
+const _TypeName = "dotjsonmermaidplantumlhtmlsvgpngdrawiographmlgexfascii"
 
-var _TypeIndex = [...]uint8{0, 3}
+var _TypeIndex = [...]uint8{0, 3, 7, 14, 22, 26, 29, 32, 38, 45, 49, 54}
 
-const _TypeLowerName = "dot"
+const _TypeLowerName = "dotjsonmermaidplantumlhtmlsvgpngdrawiographmlgexfascii"
 
 func (i Type) String() string {
 	if i < 0 || i >= Type(len(_TypeIndex)-1) {
@@ -25,17 +25,57 @@ func (i Type) String() string {
 func _TypeNoOp() {
 	var x [1]struct{}
 	_ = x[DOT-(0)]
+	_ = x[JSON-(1)]
+	_ = x[Mermaid-(2)]
+	_ = x[PlantUML-(3)]
+	_ = x[HTML-(4)]
+	_ = x[SVG-(5)]
+	_ = x[PNG-(6)]
+	_ = x[DrawIO-(7)]
+	_ = x[GraphML-(8)]
+	_ = x[GEXF-(9)]
+	_ = x[ASCII-(10)]
 }
 
-var _TypeValues = []Type{DOT}
+var _TypeValues = []Type{DOT, JSON, Mermaid, PlantUML, HTML, SVG, PNG, DrawIO, GraphML, GEXF, ASCII}
 
 var _TypeNameToValueMap = map[string]Type{
-	_TypeName[0:3]:      DOT,
-	_TypeLowerName[0:3]: DOT,
+	_TypeName[0:3]:        DOT,
+	_TypeLowerName[0:3]:   DOT,
+	_TypeName[3:7]:        JSON,
+	_TypeLowerName[3:7]:   JSON,
+	_TypeName[7:14]:       Mermaid,
+	_TypeLowerName[7:14]:  Mermaid,
+	_TypeName[14:22]:      PlantUML,
+	_TypeLowerName[14:22]: PlantUML,
+	_TypeName[22:26]:      HTML,
+	_TypeLowerName[22:26]: HTML,
+	_TypeName[26:29]:      SVG,
+	_TypeLowerName[26:29]: SVG,
+	_TypeName[29:32]:      PNG,
+	_TypeLowerName[29:32]: PNG,
+	_TypeName[32:38]:      DrawIO,
+	_TypeLowerName[32:38]: DrawIO,
+	_TypeName[38:45]:      GraphML,
+	_TypeLowerName[38:45]: GraphML,
+	_TypeName[45:49]:      GEXF,
+	_TypeLowerName[45:49]: GEXF,
+	_TypeName[49:54]:      ASCII,
+	_TypeLowerName[49:54]: ASCII,
 }
 
 var _TypeNames = []string{
 	_TypeName[0:3],
+	_TypeName[3:7],
+	_TypeName[7:14],
+	_TypeName[14:22],
+	_TypeName[22:26],
+	_TypeName[26:29],
+	_TypeName[29:32],
+	_TypeName[32:38],
+	_TypeName[38:45],
+	_TypeName[45:49],
+	_TypeName[49:54],
 }
 
 // TypeString retrieves an enum value from the enum constants string name.
@@ -21,6 +21,21 @@ var (
 		"ingress",
 		"source_security_group_id",
 		"security_group_id",
+		"vpc_id",
+		"subnet_id",
+		"subnet_ids",
+		"subnets",
+		"vpc_config",
+	}
+
+	// groupAttributes are the attributes that
+	// reference the Group of a resource
+	groupAttributes = []string{
+		"vpc_id",
+		"subnet_id",
+		"subnet_ids",
+		"subnets",
+		"vpc_config.subnet_ids",
 	}
 )
 
@@ -39,6 +54,18 @@ func (a Provider) IsEdge(resource string) bool {
 	return ok
 }
 
+// IsGroup returns if the resource is a Group
+func (a Provider) IsGroup(resource string) bool {
+	_, ok := groupTypes[resource]
+	return ok
+}
+
+// GroupAttributes returns the attributes that reference
+// the Group in which the resource is
+func (a Provider) GroupAttributes() []string {
+	return groupAttributes
+}
+
 // Resource returns the resource information
 func (a Provider) Resource(resource string) (*resource.Resource, error) {
 	r, err := tfdocAWS.GetResource(resource)
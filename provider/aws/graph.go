@@ -53,4 +53,11 @@ var (
 		"aws_security_group":      struct{}{},
 		"aws_security_group_rule": struct{}{},
 	}
+
+	// groupTypes all the Groups that we support right now,
+	// they contain the Nodes that reference them
+	groupTypes = map[string]struct{}{
+		"aws_vpc":    struct{}{},
+		"aws_subnet": struct{}{},
+	}
 )
@@ -29,6 +29,18 @@ func (a Provider) IsEdge(resource string) bool {
 	return ok
 }
 
+// IsGroup returns if the resource is a Group
+func (a Provider) IsGroup(resource string) bool {
+	_, ok := groupTypes[resource]
+	return ok
+}
+
+// GroupAttributes returns the attributes that reference
+// the Group in which the resource is
+func (a Provider) GroupAttributes() []string {
+	return groupAttributes
+}
+
 // Resource returns the resource information
 func (a Provider) Resource(resource string) (*resource.Resource, error) {
 	r, err := tfdocAzurerm.GetResource(resource)
@@ -85,6 +97,18 @@ var (
 		"virtual_network_name",
 		"id",
 		"name",
+		"subnet_id",
+		"ip_configuration",
+		"default_node_pool",
+	}
+
+	// groupAttributes are the attributes that
+	// reference the Group of a resource
+	groupAttributes = []string{
+		"virtual_network_name",
+		"subnet_id",
+		"ip_configuration.subnet_id",
+		"default_node_pool.vnet_subnet_id",
 	}
 )
 
@@ -61,4 +61,11 @@ var (
 	edgeTypes = map[string]struct{}{
 		"azurerm_virtual_network_peering": {},
 	}
+
+	// groupTypes all the Groups that we support right now,
+	// they contain the Nodes that reference them
+	groupTypes = map[string]struct{}{
+		"azurerm_virtual_network": struct{}{},
+		"azurerm_subnet":          struct{}{},
+	}
 )
@@ -30,11 +30,13 @@ var (
 )
 
 // GetProviderAndResource returns the Interface
-// and the resource name "aws_alb.front" -> "aws_alb"
+// and the resource name "aws_alb.front" -> "aws_alb",
+// the canonical can also have an instance key "aws_alb.front[0]"
 func GetProviderAndResource(can string) (provider.Provider, string, error) {
 	// Due to modules, we'll check it from the back not from
-	// the front as it may have modules prefix
-	rss := strings.Split(can, ".")
+	// the front as it may have modules prefix. The key of the
+	// instance is removed as it could also have '.'
+	rss := strings.Split(provider.TrimInstanceKey(can), ".")
 	var rs string
 	if len(rss) > 1 {
 		rs = rss[len(rss)-2]
@@ -32,6 +32,12 @@ func TestGetProviderAndResource(t *testing.T) {
 			eProvider: provider.AWS,
 			eResource: "aws_lb",
 		},
+		{
+			name:      "SuccessWithInstanceKey",
+			input:     `module.front.aws_lb.front["a.b"]`,
+			eProvider: provider.AWS,
+			eResource: "aws_lb",
+		},
 		{
 			name:   "ErrProviderNotFound-InvalidProvider",
 			input:  "pepe_a.front",
@@ -21,6 +21,18 @@ var (
 		"target_tags",
 		"source_tags",
 		"tags",
+		"network",
+		"subnetwork",
+		"network_interface",
+	}
+
+	// groupAttributes are the attributes that
+	// reference the Group of a resource
+	groupAttributes = []string{
+		"network",
+		"subnetwork",
+		"network_interface.network",
+		"network_interface.subnetwork",
 	}
 )
 
@@ -39,6 +51,18 @@ func (a Provider) IsEdge(resource string) bool {
 	return ok
 }
 
+// IsGroup is true if the resource is a Group
+func (a Provider) IsGroup(resource string) bool {
+	_, ok := groupTypes[resource]
+	return ok
+}
+
+// GroupAttributes returns the attributes that reference
+// the Group in which the resource is
+func (a Provider) GroupAttributes() []string {
+	return groupAttributes
+}
+
 // Resource returns the resource information
 func (a Provider) Resource(resource string) (*resource.Resource, error) {
 	r, err := tfdocGCP.GetResource(resource)
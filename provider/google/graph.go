@@ -217,4 +217,11 @@ var (
 	edgeTypes = map[string]struct{}{
 		"google_compute_firewall": struct{}{},
 	}
+
+	// groupTypes all the Groups that we support right now,
+	// they contain the Nodes that reference them
+	groupTypes = map[string]struct{}{
+		"google_compute_network":    struct{}{},
+		"google_compute_subnetwork": struct{}{},
+	}
 )
@@ -21,6 +21,14 @@ func (n NopProvider) IsNode(rsc string) bool { return false }
 // an Edge or not
 func (n NopProvider) IsEdge(rsc string) bool { return false }
 
+// IsGroup checks if the resource should be considered
+// a Group that contains Nodes, like a VPC or a Subnet
+func (n NopProvider) IsGroup(rsc string) bool { return false }
+
+// GroupAttributes returns the attributes of the resources
+// that reference the Group they belong to
+func (n NopProvider) GroupAttributes() []string { return nil }
+
 // Resource returns the resource information
 func (n NopProvider) Resource(rsc string) (*resource.Resource, error) { return nil, nil }
 
@@ -85,4 +85,11 @@ var (
 		"openstack_lb_pool_v2":                  struct{}{},
 		"openstack_lb_member_v2":                struct{}{},
 	}
+
+	// groupTypes all the Groups that we support right now,
+	// they contain the Nodes that reference them
+	groupTypes = map[string]struct{}{
+		"openstack_networking_network_v2": struct{}{},
+		"openstack_networking_subnet_v2":  struct{}{},
+	}
 )
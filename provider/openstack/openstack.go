@@ -24,6 +24,20 @@ var (
 		"loadbalancer_id",
 		"listener_id",
 		"pool_id",
+		"network_id",
+		"subnet_id",
+		"network",
+		"fixed_ip",
+	}
+
+	// groupAttributes are the attributes that
+	// reference the Group of a resource
+	groupAttributes = []string{
+		"network_id",
+		"subnet_id",
+		"network.uuid",
+		"network.name",
+		"fixed_ip.subnet_id",
 	}
 )
 
@@ -42,6 +56,18 @@ func (a Provider) IsEdge(resource string) bool {
 	return ok
 }
 
+// IsGroup returns if the resource is a Group
+func (a Provider) IsGroup(resource string) bool {
+	_, ok := groupTypes[resource]
+	return ok
+}
+
+// GroupAttributes returns the attributes that reference
+// the Group in which the resource is
+func (a Provider) GroupAttributes() []string {
+	return groupAttributes
+}
+
 // Resource returns the resource information
 func (a Provider) Resource(resource string) (*resource.Resource, error) {
 	r, err := tfdocOS.GetResource(resource)
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"regexp"
+
 	"github.com/cycloidio/tfdocs/resource"
 )
 
@@ -9,6 +11,17 @@ import (
 // Canonical of the Resource (ex: _im_canonical => aws_lb.front)
 const HCLCanonicalKey = "_im_canonical"
 
+// reInstanceKey matches the key of the instance at the end of
+// a canonical, like '[0]' or '["a.b"]'
+var reInstanceKey = regexp.MustCompile(`\[(?:[0-9]+|"(?:[^"\\]|\\.)*")\]$`)
+
+// TrimInstanceKey returns the can without the key of the instance
+// (from a 'count' or 'for_each') if it has one, so
+// 'aws_instance.web["a"]' -> 'aws_instance.web'
+func TrimInstanceKey(can string) string {
+	return reInstanceKey.ReplaceAllString(can, "")
+}
+
 // Provider is an interface to abstract common functions on all the
 // providers
 type Provider interface {
@@ -23,6 +36,16 @@ type Provider interface {
 	// an Edge or not
 	IsEdge(rsc string) bool
 
+	// IsGroup checks if the resource should be considered
+	// a Group that contains Nodes, like a VPC or a Subnet
+	IsGroup(rsc string) bool
+
+	// GroupAttributes returns the attributes of the resources
+	// that reference the Group they belong to, like the 'subnet_id'.
+	// The nested attributes are separated by '.', like
+	// 'network_interface.subnetwork'
+	GroupAttributes() []string
+
 	// Resource returns the resource information
 	Resource(rsc string) (*resource.Resource, error)
 
@@ -61,8 +61,8 @@ func Prune(tfstate json.RawMessage, replaceCanonicals bool) (json.RawMessage, er
 				return nil, err
 			}
 
-			// If it's not a Node or Edge we append it to delete
-			if !pv.IsNode(rs) && !pv.IsEdge(rs) {
+			// If it's not a Node, Edge or Group we append it to delete
+			if !pv.IsNode(rs) && !pv.IsEdge(rs) && !pv.IsGroup(rs) {
 				removeKeys = append(removeKeys, rk)
 				continue
 			}